@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+// vcsCatalog is a catalog tagged with the VCS lists it was found in.
+type vcsCatalog struct {
+	catalogWithURL
+	SourceVCS []string `json:"source_vcs"`
+}
+
+// mergeByVCS combines per-VCS catalog lists in the order given, keeping one
+// entry per catalog ID and recording every VCS that returned it.
+func mergeByVCS(baseURL string, vcsList []string, lists [][]*enbuild.Catalog) []*vcsCatalog {
+	merged := []*vcsCatalog{}
+	byID := map[string]*vcsCatalog{}
+	for i, catalogs := range lists {
+		for _, c := range catalogs {
+			id := fmt.Sprint(c.ID)
+			if existing, ok := byID[id]; ok {
+				existing.SourceVCS = append(existing.SourceVCS, vcsList[i])
+				continue
+			}
+			entry := &vcsCatalog{catalogWithURL: withWebURL(baseURL, c), SourceVCS: []string{vcsList[i]}}
+			byID[id] = entry
+			merged = append(merged, entry)
+		}
+	}
+	return merged
+}
+
+// listCatalogsPerVCS runs the same list call for each VCS concurrently, one
+// call per provider, so a search across providers takes as long as the
+// slowest one rather than their sum. The outbound rate limiter still
+// applies. Lists and errors are returned in the order of vcsList.
+func listCatalogsPerVCS(ctx context.Context, client CatalogService, vcsList []string, opts *enbuild.CatalogListOptions) ([][]*enbuild.Catalog, []error) {
+	lists := make([][]*enbuild.Catalog, len(vcsList))
+	errs := make([]error, len(vcsList))
+	var wg sync.WaitGroup
+	for i, v := range vcsList {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			vcsOpts := *opts
+			vcsOpts.VCS = v
+			lists[i], errs[i] = listCatalogsByTypes(ctx, client, &vcsOpts)
+		}()
+	}
+	wg.Wait()
+	return lists, errs
+}
+
+// listFilteredCatalogs lists the catalogs matching the search filters in
+// opts. With vcs ALL every provider is searched in parallel and the results
+// are merged by catalog ID; if only some providers fail, their catalogs are
+// left out and the returned warning says so. It fails only when every
+// provider does.
+func listFilteredCatalogs(ctx context.Context, client CatalogService, opts *enbuild.CatalogListOptions) ([]*enbuild.Catalog, string, error) {
+	if opts.VCS != allVCS {
+		catalogs, err := listCatalogsByTypes(ctx, client, opts)
+		return catalogs, "", err
+	}
+
+	lists, errs := listCatalogsPerVCS(ctx, client, SupportedVCS, opts)
+	var merged []*enbuild.Catalog
+	var failed, reasons []string
+	var failures []error
+	seen := map[string]bool{}
+	for i, catalogs := range lists {
+		if errs[i] != nil {
+			failed = append(failed, SupportedVCS[i])
+			failures = append(failures, fmt.Errorf("%s: %w", SupportedVCS[i], errs[i]))
+			reasons = append(reasons, failures[len(failures)-1].Error())
+			continue
+		}
+		for _, c := range catalogs {
+			id := fmt.Sprint(c.ID)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			merged = append(merged, c)
+		}
+	}
+	if len(failures) == len(SupportedVCS) {
+		return nil, "", errors.Join(failures...)
+	}
+	if len(failures) > 0 {
+		return merged, fmt.Sprintf("; warning: listing failed for VCS %s, so its catalogs are missing: %s", strings.Join(failed, ", "), strings.Join(reasons, "; ")), nil
+	}
+	return merged, "", nil
+}
+
+func listCatalogsByVCS(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	vcs, _ := request.GetArguments()["vcs"].(string)
+	vcs = strings.ToUpper(strings.TrimSpace(vcs))
+	vcsList := SupportedVCS
+	switch {
+	case vcs == "" || vcs == allVCS:
+	case isSupportedVCS(vcs):
+		vcsList = []string{vcs}
+	default:
+		return formatValidationErrorResponse("Invalid VCS value", fmt.Errorf("VCS must be %s", vcsChoices(allVCS)))
+	}
+	catalogType, _ := request.GetArguments()["type"].(string)
+	catalogType = normalizeCatalogTypeFilter(catalogType)
+
+	creds, err := getCredentials(request)
+	if err != nil {
+		return formatCredentialsErrorResponse(err)
+	}
+
+	client, err := initializeClient(ctx, creds)
+	if err != nil {
+		return formatErrorResponse("Failed to initialize ENBUILD client", err)
+	}
+
+	lists, errs := listCatalogsPerVCS(ctx, client, vcsList, &enbuild.CatalogListOptions{Type: catalogType})
+	for i, err := range errs {
+		if err != nil {
+			return formatCallErrorResponse(fmt.Sprintf("Failed to list catalogs for VCS: %s", vcsList[i]), err)
+		}
+	}
+
+	merged := mergeByVCS(creds.BaseURL, vcsList, lists)
+	sort.SliceStable(merged, func(i, j int) bool {
+		return compareFold(merged[i].Name, merged[j].Name, false)
+	})
+
+	response := CatalogResponse{
+		Success: true,
+		Count:   len(merged),
+		Data:    merged,
+		Message: fmt.Sprintf("Successfully retrieved %d catalogs across VCS: %s", len(merged), strings.Join(vcsList, ", ")),
+	}
+
+	return formatJSONResponse(response)
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// auditMaxBytes is the size at which the audit log is rotated.
+	auditMaxBytes = 10 << 20
+	// auditBackups is how many rotated audit logs are kept, as path.1
+	// (newest) to path.N.
+	auditBackups = 5
+)
+
+// auditRedactedArguments are the tool arguments whose values never reach
+// the audit log.
+var auditRedactedArguments = map[string]bool{
+	"username":      true,
+	"password":      true,
+	"token":         true,
+	"client_id":     true,
+	"client_secret": true,
+}
+
+// auditLog is the --audit-log writer; nil when auditing is off.
+var auditLog *auditLogger
+
+type auditEntry struct {
+	Time       string                 `json:"time"`
+	Tool       string                 `json:"tool"`
+	RequestID  string                 `json:"request_id,omitempty"`
+	Arguments  map[string]interface{} `json:"arguments,omitempty"`
+	Outcome    string                 `json:"outcome"`
+	ErrorType  string                 `json:"error_type,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	DurationMS int64                  `json:"duration_ms"`
+}
+
+// auditLogger appends JSON lines to a file, rotating it when it reaches
+// auditMaxBytes.
+type auditLogger struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	size int64
+}
+
+func openAuditLog(path string) (*auditLogger, error) {
+	l := &auditLogger{path: path}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *auditLogger) open() error {
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	l.file, l.size = f, info.Size()
+	return nil
+}
+
+// rotate shifts path.N-1 to path.N down to path to path.1, dropping the
+// oldest, and starts a new file.
+func (l *auditLogger) rotate() error {
+	l.file.Close()
+	for i := auditBackups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", l.path, i), fmt.Sprintf("%s.%d", l.path, i+1))
+	}
+	renameErr := os.Rename(l.path, l.path+".1")
+	// Keep logging to the old file if it could not be moved aside.
+	if err := l.open(); err != nil {
+		l.file = nil
+		return err
+	}
+	return renameErr
+}
+
+func (l *auditLogger) write(entry auditEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		warnf("Could not encode audit entry for %s: %v", entry.Tool, err)
+		return
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.size > 0 && l.size+int64(len(line)) > auditMaxBytes {
+		if err := l.rotate(); err != nil {
+			warnf("Could not rotate audit log %s: %v", l.path, err)
+		}
+	}
+	if l.file == nil {
+		return
+	}
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	if err != nil {
+		warnf("Could not write audit log %s: %v", l.path, err)
+	}
+}
+
+func (l *auditLogger) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file != nil {
+		l.file.Close()
+		l.file = nil
+	}
+}
+
+// configureAuditLog opens the file named by ENBUILD_AUDIT_LOG. The returned
+// function closes it.
+func configureAuditLog() (func(), error) {
+	path := os.Getenv("ENBUILD_AUDIT_LOG")
+	if path == "" {
+		return func() {}, nil
+	}
+	l, err := openAuditLog(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open audit log: %v", err)
+	}
+	auditLog = l
+	infof("Writing audit log to %s", path)
+	return func() {
+		auditLog = nil
+		l.close()
+	}, nil
+}
+
+// auditArguments copies the call's arguments with credentials redacted.
+func auditArguments(args map[string]interface{}) map[string]interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	copied := make(map[string]interface{}, len(args))
+	for name, value := range args {
+		if auditRedactedArguments[name] {
+			value = redacted
+		}
+		copied[name] = value
+	}
+	return copied
+}
+
+// auditMiddleware writes one audit entry per tool call. It runs inside
+// requestIDMiddleware so entries carry the call's request ID.
+func auditMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		l := auditLog
+		if l == nil {
+			return next(ctx, request)
+		}
+		start := time.Now()
+		result, err := next(ctx, request)
+
+		entry := auditEntry{
+			Time:       start.UTC().Format(time.RFC3339Nano),
+			Tool:       request.Params.Name,
+			Arguments:  auditArguments(request.GetArguments()),
+			Outcome:    "success",
+			DurationMS: time.Since(start).Milliseconds(),
+		}
+		entry.RequestID, _ = ctx.Value(requestIDKey{}).(string)
+		switch {
+		case err != nil:
+			entry.Outcome, entry.Error = "error", err.Error()
+		case result == nil:
+			entry.Outcome = "error"
+		case result.IsError:
+			entry.Outcome = "error"
+			var response CatalogResponse
+			if len(result.Content) == 1 {
+				if text, ok := result.Content[0].(mcp.TextContent); ok {
+					json.Unmarshal([]byte(text.Text), &response)
+				}
+			}
+			entry.ErrorType, entry.Error = response.ErrorType, response.Message
+		}
+		l.write(entry)
+		return result, err
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// backendConfig is one entry of the config file's backends map: another
+// ENBUILD instance that tool calls can select with the backend argument.
+type backendConfig struct {
+	BaseURL      string `yaml:"base_url"`
+	Username     string `yaml:"username"`
+	Password     string `yaml:"password"`
+	Token        string `yaml:"token"`
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+}
+
+// backends are the named backends from the config file. A call without a
+// backend argument uses the server's own base URL and credentials.
+var backends = map[string]backendConfig{}
+
+// configureBackends checks and stores the config file's backends. Each one
+// needs a base URL and a complete set of credentials, since calls that
+// select it fall back to them instead of the server's.
+func configureBackends(configured map[string]backendConfig) error {
+	loaded := make(map[string]backendConfig, len(configured))
+	for name, b := range configured {
+		if name == "" || strings.TrimSpace(name) != name {
+			return fmt.Errorf("backend name %q must not be empty or have surrounding spaces", name)
+		}
+		baseURL, err := normalizeBaseURL(b.BaseURL)
+		if err != nil {
+			return fmt.Errorf("backend %s: %v", name, err)
+		}
+		b.BaseURL = baseURL
+		if (b.ClientID == "") != (b.ClientSecret == "") || (b.Username == "") != (b.Password == "") {
+			return fmt.Errorf("backend %s sets only one of client_id and client_secret, or of username and password", name)
+		}
+		if b.Token == "" && b.ClientID == "" && b.Username == "" {
+			return fmt.Errorf("backend %s needs a token, client_id and client_secret, or username and password", name)
+		}
+		loaded[name] = b
+	}
+	backends = loaded
+	return nil
+}
+
+func backendNames() []string {
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupBackend returns the named backend, or an error listing the
+// configured ones.
+func lookupBackend(name string) (backendConfig, error) {
+	b, ok := backends[name]
+	if ok {
+		return b, nil
+	}
+	if len(backends) == 0 {
+		return backendConfig{}, fmt.Errorf("unknown backend %q: no backends are configured", name)
+	}
+	return backendConfig{}, fmt.Errorf("unknown backend %q: configured backends are %s", name, strings.Join(backendNames(), ", "))
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+const (
+	// maxBatchIDs caps how many catalogs one get_catalogs call may fetch.
+	maxBatchIDs = 50
+	// batchWorkers is how many catalogs get_catalogs fetches at once.
+	batchWorkers = 4
+)
+
+type batchEntry struct {
+	ID         string          `json:"id"`
+	Catalog    *catalogWithURL `json:"catalog"`
+	Error      string          `json:"error,omitempty"`
+	StatusCode int             `json:"status_code,omitempty"`
+}
+
+// getIDsArgument reads the ids argument, which may be an array of strings,
+// a JSON array in a string, or a comma-separated string. Blank and repeated
+// IDs are dropped.
+func getIDsArgument(request mcp.CallToolRequest) ([]string, error) {
+	var raw []string
+	switch v := request.GetArguments()["ids"].(type) {
+	case nil:
+	case []interface{}:
+		for _, item := range v {
+			id, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("ids must be strings")
+			}
+			raw = append(raw, id)
+		}
+	case string:
+		if strings.HasPrefix(strings.TrimSpace(v), "[") {
+			if err := json.Unmarshal([]byte(v), &raw); err != nil {
+				return nil, fmt.Errorf("ids is not a valid JSON array of strings: %v", err)
+			}
+		} else {
+			raw = strings.Split(v, ",")
+		}
+	default:
+		return nil, fmt.Errorf("ids must be an array or a comma-separated string")
+	}
+
+	seen := map[string]bool{}
+	var ids []string
+	for _, id := range raw {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("at least one catalog ID is required")
+	}
+	if len(ids) > maxBatchIDs {
+		return nil, fmt.Errorf("at most %d catalog IDs may be requested at once, got %d", maxBatchIDs, len(ids))
+	}
+	return ids, nil
+}
+
+// fetchCatalogs gets each catalog with a bounded number of concurrent
+// calls. Entries are returned in the order of ids.
+func fetchCatalogs(ctx context.Context, client CatalogService, baseURL string, ids []string) []batchEntry {
+	entries := make([]batchEntry, len(ids))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(batchWorkers, len(ids)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				entries[i].ID = ids[i]
+				catalog, err := getCatalogContext(ctx, client, ids[i], &enbuild.CatalogListOptions{})
+				if err != nil {
+					entries[i].Error = err.Error()
+					entries[i].StatusCode = statusCodeFromError(err)
+					continue
+				}
+				c := withWebURL(baseURL, catalog)
+				entries[i].Catalog = &c
+			}
+		}()
+	}
+	for i := range ids {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return entries
+}
+
+func getCatalogs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ids, err := getIDsArgument(request)
+	if err != nil {
+		return formatValidationErrorResponse("Invalid ids value", err)
+	}
+
+	creds, err := getCredentials(request)
+	if err != nil {
+		return formatCredentialsErrorResponse(err)
+	}
+
+	client, err := initializeClient(ctx, creds)
+	if err != nil {
+		return formatErrorResponse("Failed to initialize ENBUILD client", err)
+	}
+
+	entries := fetchCatalogs(ctx, client, creds.BaseURL, ids)
+	fetched := 0
+	for _, entry := range entries {
+		if entry.Catalog != nil {
+			fetched++
+		}
+	}
+
+	message := fmt.Sprintf("Successfully retrieved %d of %d catalogs", fetched, len(ids))
+	if failed := len(ids) - fetched; failed > 0 {
+		message += fmt.Sprintf("; %d failed, see the error of each entry", failed)
+	}
+	response := CatalogResponse{
+		Success: true,
+		Count:   fetched,
+		Data:    entries,
+		Message: message,
+	}
+
+	return formatJSONResponse(response)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// catalogCache is shared by every client, since the handlers build a new
+// client per call. Keys include a fingerprint of the credentials so callers
+// with different access never share entries.
+var catalogCache = struct {
+	sync.Mutex
+	entries map[string]cacheEntry
+}{entries: map[string]cacheEntry{}}
+
+func cacheTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("ENBUILD_CACHE_TTL"))
+	if err != nil || ttl < 0 {
+		return 0
+	}
+	return ttl
+}
+
+func cacheGet(key string) (interface{}, bool) {
+	catalogCache.Lock()
+	defer catalogCache.Unlock()
+	entry, ok := catalogCache.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(catalogCache.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func cacheSet(key string, value interface{}, ttl time.Duration) {
+	catalogCache.Lock()
+	defer catalogCache.Unlock()
+	now := time.Now()
+	for k, entry := range catalogCache.entries {
+		if now.After(entry.expires) {
+			delete(catalogCache.entries, k)
+		}
+	}
+	catalogCache.entries[key] = cacheEntry{value: value, expires: now.Add(ttl)}
+}
+
+// cachedCatalogService serves repeated List and Get calls from catalogCache
+// for ttl. List and Get results use separate key namespaces.
+type cachedCatalogService struct {
+	CatalogService
+	prefix string
+	ttl    time.Duration
+}
+
+// credentialsKey identifies a set of credentials without holding the
+// secrets themselves.
+func credentialsKey(creds credentials) string {
+	sum := sha256.Sum256([]byte(creds.BaseURL + "\x00" + creds.Username + "\x00" + creds.Password + "\x00" + creds.Token + "\x00" + creds.ClientID + "\x00" + creds.ClientSecret))
+	return hex.EncodeToString(sum[:8])
+}
+
+func newCachedCatalogService(service CatalogService, creds credentials, ttl time.Duration) cachedCatalogService {
+	return cachedCatalogService{CatalogService: service, prefix: credentialsKey(creds), ttl: ttl}
+}
+
+func (s cachedCatalogService) key(namespace string, v interface{}) string {
+	data, _ := json.Marshal(v)
+	return namespace + ":" + s.prefix + ":" + string(data)
+}
+
+func (s cachedCatalogService) List(opts ...*enbuild.CatalogListOptions) ([]*enbuild.Catalog, error) {
+	key := s.key("list", opts)
+	if cached, ok := cacheGet(key); ok {
+		debugf("Catalog cache hit: %s", key)
+		// Callers sort and slice the result, so each gets its own copy.
+		return append([]*enbuild.Catalog(nil), cached.([]*enbuild.Catalog)...), nil
+	}
+	catalogs, err := s.CatalogService.List(opts...)
+	if err != nil {
+		return nil, err
+	}
+	cacheSet(key, append([]*enbuild.Catalog(nil), catalogs...), s.ttl)
+	return catalogs, nil
+}
+
+func (s cachedCatalogService) Get(id string, opts *enbuild.CatalogListOptions) (*enbuild.Catalog, error) {
+	key := s.key("get", []interface{}{id, opts})
+	if cached, ok := cacheGet(key); ok {
+		debugf("Catalog cache hit: %s", key)
+		return cached.(*enbuild.Catalog), nil
+	}
+	catalog, err := s.CatalogService.Get(id, opts)
+	if err != nil {
+		return nil, err
+	}
+	cacheSet(key, catalog, s.ttl)
+	return catalog, nil
+}
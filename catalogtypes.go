@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+// catalogTypes returns the distinct non-empty catalog types, sorted.
+func catalogTypes(catalogs []*enbuild.Catalog) []string {
+	seen := map[string]bool{}
+	types := []string{}
+	for _, c := range catalogs {
+		t := strings.TrimSpace(c.Type)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func listCatalogTypes(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	opts := &enbuild.CatalogListOptions{}
+	if vcs, _ := request.GetArguments()["vcs"].(string); vcs != "" {
+		opts.VCS = strings.ToUpper(vcs)
+		if !isSupportedVCS(opts.VCS) {
+			return formatValidationErrorResponse("Invalid VCS value", fmt.Errorf("VCS must be either %s", vcsChoices()))
+		}
+	}
+
+	creds, err := getCredentials(request)
+	if err != nil {
+		return formatCredentialsErrorResponse(err)
+	}
+
+	client, err := initializeClient(ctx, creds)
+	if err != nil {
+		return formatErrorResponse("Failed to initialize ENBUILD client", err)
+	}
+
+	// The API has no endpoint for types, so they are collected from the
+	// catalog list.
+	catalogs, err := listCatalogsContext(ctx, client, opts)
+	if err != nil {
+		return formatCallErrorResponse("Failed to list catalogs", err)
+	}
+
+	types := catalogTypes(catalogs)
+	message := fmt.Sprintf("Found %d catalog types across %d catalogs", len(types), len(catalogs))
+	if opts.VCS != "" {
+		message += " for VCS: " + opts.VCS
+	}
+	response := CatalogResponse{
+		Success: true,
+		Count:   len(types),
+		Data:    types,
+		Message: message,
+	}
+
+	return formatJSONResponse(response)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+// clients holds one catalog service per set of credentials, so tool calls
+// reuse an authenticated SDK client instead of logging in to Keycloak on
+// every call. Each client refreshes its token as it expires.
+var clients = struct {
+	sync.Mutex
+	services map[string]CatalogService
+}{services: map[string]CatalogService{}}
+
+// sharedCatalogService returns the cached service for creds, creating it on
+// first use. The client is created without holding the lock, so a slow
+// login does not block calls with other credentials; if two calls race,
+// the first to finish is kept.
+func sharedCatalogService(creds credentials) (CatalogService, error) {
+	key := credentialsKey(creds)
+
+	clients.Lock()
+	service, ok := clients.services[key]
+	clients.Unlock()
+	if ok {
+		return service, nil
+	}
+
+	created, err := newCatalogService(creds)
+	if err != nil {
+		return nil, err
+	}
+	service = evictOnAuthError{CatalogService: created, key: key, diskKey: diskTokenCacheKey(creds)}
+
+	clients.Lock()
+	defer clients.Unlock()
+	if existing, ok := clients.services[key]; ok {
+		return existing, nil
+	}
+	clients.services[key] = service
+	debugf("Created ENBUILD client for %s", creds.BaseURL)
+	return service, nil
+}
+
+// evictClient drops the cached service for key, if it is still cached.
+func evictClient(key string) {
+	clients.Lock()
+	defer clients.Unlock()
+	if _, ok := clients.services[key]; ok {
+		delete(clients.services, key)
+		debugf("Discarded cached ENBUILD client after an authentication error")
+	}
+}
+
+// resetClients drops every cached service and returns how many there were.
+func resetClients() int {
+	clients.Lock()
+	defer clients.Unlock()
+	n := len(clients.services)
+	clients.services = map[string]CatalogService{}
+	return n
+}
+
+// evictOnAuthError discards its cached client, and any token cached on disk
+// for it, when a call fails with an authentication error, so the next call
+// logs in again and picks up a rotated password or token.
+type evictOnAuthError struct {
+	CatalogService
+	key     string
+	diskKey string
+}
+
+func (s evictOnAuthError) check(err error) {
+	if err != nil && classifyPingError(err) == errorCodeAuthFailed {
+		evictClient(s.key)
+		removeCachedToken(s.diskKey)
+	}
+}
+
+// withContext binds ctx to the wrapped service if it supports that.
+func (s evictOnAuthError) withContext(ctx context.Context) CatalogService {
+	if b, ok := s.CatalogService.(contextBinder); ok {
+		s.CatalogService = b.withContext(ctx)
+	}
+	return s
+}
+
+func (s evictOnAuthError) List(opts ...*enbuild.CatalogListOptions) ([]*enbuild.Catalog, error) {
+	catalogs, err := s.CatalogService.List(opts...)
+	s.check(err)
+	return catalogs, err
+}
+
+func (s evictOnAuthError) Get(id string, opts *enbuild.CatalogListOptions) (*enbuild.Catalog, error) {
+	catalog, err := s.CatalogService.Get(id, opts)
+	s.check(err)
+	return catalog, err
+}
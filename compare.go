@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+// fieldDifference holds a field's value in each of the compared catalogs.
+type fieldDifference struct {
+	A interface{} `json:"a"`
+	B interface{} `json:"b"`
+}
+
+// catalogComparison is the result of compare_catalogs. Nested objects such
+// as content are compared field by field and named by their path, e.g.
+// content.inputs.region; arrays are compared as a whole.
+type catalogComparison struct {
+	IDA       string                     `json:"id_a"`
+	IDB       string                     `json:"id_b"`
+	Same      []string                   `json:"same"`
+	Different map[string]fieldDifference `json:"different"`
+	OnlyInA   map[string]interface{}     `json:"only_in_a"`
+	OnlyInB   map[string]interface{}     `json:"only_in_b"`
+}
+
+// flattenCatalog returns the catalog's JSON fields keyed by dotted path.
+func flattenCatalog(c *enbuild.Catalog) (map[string]interface{}, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	flat := map[string]interface{}{}
+	flattenInto(flat, "", fields)
+	return flat, nil
+}
+
+func flattenInto(flat map[string]interface{}, prefix string, fields map[string]interface{}) {
+	for key, value := range fields {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenInto(flat, path, nested)
+			continue
+		}
+		flat[path] = value
+	}
+}
+
+func compareCatalogFields(a, b map[string]interface{}) catalogComparison {
+	comparison := catalogComparison{
+		Same:      []string{},
+		Different: map[string]fieldDifference{},
+		OnlyInA:   map[string]interface{}{},
+		OnlyInB:   map[string]interface{}{},
+	}
+	for path, valueA := range a {
+		valueB, ok := b[path]
+		switch {
+		case !ok:
+			comparison.OnlyInA[path] = valueA
+		case reflect.DeepEqual(valueA, valueB):
+			comparison.Same = append(comparison.Same, path)
+		default:
+			comparison.Different[path] = fieldDifference{A: valueA, B: valueB}
+		}
+	}
+	for path, valueB := range b {
+		if _, ok := a[path]; !ok {
+			comparison.OnlyInB[path] = valueB
+		}
+	}
+	sort.Strings(comparison.Same)
+	return comparison
+}
+
+func compareCatalogs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	idA, _ := request.GetArguments()["id_a"].(string)
+	idB, _ := request.GetArguments()["id_b"].(string)
+	if idA == "" || idB == "" {
+		return formatValidationErrorResponse("Missing required parameter", fmt.Errorf("both id_a and id_b are required"))
+	}
+	if idA == idB {
+		return formatValidationErrorResponse("Invalid parameters", fmt.Errorf("id_a and id_b must name different catalogs"))
+	}
+
+	creds, err := getCredentials(request)
+	if err != nil {
+		return formatCredentialsErrorResponse(err)
+	}
+
+	client, err := initializeClient(ctx, creds)
+	if err != nil {
+		return formatErrorResponse("Failed to initialize ENBUILD client", err)
+	}
+
+	var flat [2]map[string]interface{}
+	for i, id := range []string{idA, idB} {
+		catalog, err := getCatalogContext(ctx, client, id, &enbuild.CatalogListOptions{})
+		if err != nil {
+			return formatCallErrorResponse(fmt.Sprintf("Failed to get catalog %s", id), err)
+		}
+		if flat[i], err = flattenCatalog(catalog); err != nil {
+			return formatErrorResponse(fmt.Sprintf("Failed to read catalog %s", id), err)
+		}
+	}
+
+	comparison := compareCatalogFields(flat[0], flat[1])
+	comparison.IDA, comparison.IDB = idA, idB
+	response := CatalogResponse{
+		Success: true,
+		Count:   2,
+		Data:    comparison,
+		Message: fmt.Sprintf("Catalogs %s and %s share %d fields, differ in %d, and have %d and %d fields the other lacks", idA, idB, len(comparison.Same), len(comparison.Different), len(comparison.OnlyInA), len(comparison.OnlyInB)),
+	}
+
+	return formatJSONResponse(response)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultProfileName is the profile used when neither --profile nor the
+// file's default_profile names one.
+const defaultProfileName = "default"
+
+// fileConfig is the --config file. YAML and JSON are both accepted, since
+// JSON documents are valid YAML. Named profiles override the top-level
+// values for one ENBUILD instance each.
+type fileConfig struct {
+	Username   string `yaml:"username"`
+	Password   string `yaml:"password"`
+	Token      string `yaml:"token"`
+	BaseURL    string `yaml:"base_url"`
+	Timeout    string `yaml:"timeout"`
+	DefaultVCS string `yaml:"default_vcs"`
+
+	// Environments adds or overrides --env shortcuts, mapping a name to a
+	// base URL. It is only read from the top level of the file.
+	Environments map[string]string `yaml:"environments"`
+	// Backends names other ENBUILD instances that tool calls can select
+	// with the backend argument. It is only read from the top level.
+	Backends map[string]backendConfig `yaml:"backends"`
+
+	DefaultProfile string                `yaml:"default_profile"`
+	Profiles       map[string]fileConfig `yaml:"profiles"`
+}
+
+// loadConfigFile reads the config file and merges the selected profile over
+// its top-level values. An empty profile selects default_profile, then a
+// profile named "default" if one exists.
+func loadConfigFile(path, profile string) (fileConfig, error) {
+	var cfg fileConfig
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read config file: %v", err)
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("config file %s must be a YAML or JSON object: %v", path, err)
+	}
+
+	explicit := profile != ""
+	if profile == "" {
+		profile = cfg.DefaultProfile
+		explicit = profile != ""
+	}
+	if profile == "" {
+		profile = defaultProfileName
+	}
+	if p, ok := cfg.Profiles[profile]; ok {
+		cfg = cfg.merge(p)
+	} else if explicit {
+		return cfg, fmt.Errorf("config file %s has no profile named %q", path, profile)
+	}
+
+	if cfg.Timeout != "" {
+		if _, err := time.ParseDuration(cfg.Timeout); err != nil {
+			return cfg, fmt.Errorf("config file %s has an invalid timeout %q: %v", path, cfg.Timeout, err)
+		}
+	}
+	return cfg, nil
+}
+
+// applyEnv fills ENBUILD_* environment variables that neither a flag nor
+// the environment already provide, so flags > env vars > config file.
+func (cfg fileConfig) applyEnv(ec enbuildConfig, baseURLFlagSet bool) {
+	// A token from the config file would replace username/password or client
+	// credentials given by a flag or env var, so it is only used when no
+	// credentials are set.
+	if ec.username == "" && ec.password == "" && ec.token == "" && ec.clientID == "" &&
+		os.Getenv("ENBUILD_USERNAME") == "" && os.Getenv("ENBUILD_PASSWORD") == "" && os.Getenv("ENBUILD_CLIENT_ID") == "" {
+		setEnvIfUnset("ENBUILD_TOKEN", cfg.Token)
+	}
+	if ec.username == "" {
+		setEnvIfUnset("ENBUILD_USERNAME", cfg.Username)
+	}
+	if ec.password == "" {
+		setEnvIfUnset("ENBUILD_PASSWORD", cfg.Password)
+	}
+	if !baseURLFlagSet {
+		setEnvIfUnset("ENBUILD_BASE_URL", cfg.BaseURL)
+	}
+	setEnvIfUnset("ENBUILD_TIMEOUT", cfg.Timeout)
+	setEnvIfUnset("ENBUILD_DEFAULT_VCS", cfg.DefaultVCS)
+}
+
+func (cfg fileConfig) merge(p fileConfig) fileConfig {
+	for dst, src := range map[*string]string{
+		&cfg.Username:   p.Username,
+		&cfg.Password:   p.Password,
+		&cfg.Token:      p.Token,
+		&cfg.BaseURL:    p.BaseURL,
+		&cfg.Timeout:    p.Timeout,
+		&cfg.DefaultVCS: p.DefaultVCS,
+	} {
+		if src != "" {
+			*dst = src
+		}
+	}
+	return cfg
+}
+
+func setEnvIfUnset(envVar, value string) {
+	if value != "" && os.Getenv(envVar) == "" {
+		os.Setenv(envVar, value)
+	}
+}
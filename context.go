@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+// contextBinder is implemented by services that can send their requests
+// with a caller's context. initializeClient binds each call's ctx to the
+// shared service through it.
+type contextBinder interface {
+	withContext(ctx context.Context) CatalogService
+}
+
+// callWithContext runs call and returns early with ctx's error if ctx is
+// cancelled first. The SDK does not accept a context, so an abandoned SDK
+// call keeps running in the background until its own timeout, but the
+// handler no longer waits for it. Token and Keycloak logins are bound to
+// ctx, so their requests are aborted as well.
+func callWithContext[T any](ctx context.Context, call func() (T, error)) (T, error) {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		// The goroutine is outside the server's panic recovery, and the SDK
+		// panics on some responses, e.g. Get of an empty data array.
+		defer func() {
+			if r := recover(); r != nil {
+				done <- result{err: fmt.Errorf("ENBUILD SDK panic: %v", r)}
+			}
+		}()
+		value, err := call()
+		done <- result{value, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// listCatalogsContext and getCatalogContext are the only places the SDK is
+// called, so they also record its calls as trace spans.
+func listCatalogsContext(ctx context.Context, client CatalogService, opts ...*enbuild.CatalogListOptions) ([]*enbuild.Catalog, error) {
+	_, s := startSpan(ctx, "enbuild.catalogs.list", spanKindClient)
+	for _, o := range opts {
+		catalogListAttributes(s, o)
+	}
+	catalogs, err := callWithContext(ctx, func() ([]*enbuild.Catalog, error) { return client.List(opts...) })
+	if err == nil {
+		s.setAttribute("enbuild.result_count", strconv.Itoa(len(catalogs)))
+	}
+	s.finish(err)
+	return catalogs, err
+}
+
+func getCatalogContext(ctx context.Context, client CatalogService, id string, opts *enbuild.CatalogListOptions) (*enbuild.Catalog, error) {
+	_, s := startSpan(ctx, "enbuild.catalogs.get", spanKindClient)
+	s.setAttribute("enbuild.catalog_id", id)
+	catalogListAttributes(s, opts)
+	catalog, err := callWithContext(ctx, func() (*enbuild.Catalog, error) { return client.Get(id, opts) })
+	s.finish(err)
+	return catalog, err
+}
+
+// formatCallErrorResponse reports a failed API call, calling out requests
+// that were cancelled or timed out by the client.
+func formatCallErrorResponse(message string, err error) (*mcp.CallToolResult, error) {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return formatErrorResponse("Request was cancelled before ENBUILD responded", err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return formatErrorResponse("Request deadline passed before ENBUILD responded", err)
+	}
+	return formatErrorResponse(message, err)
+}
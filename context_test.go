@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+// redirectTransport sends every request to target, since the SDK rewrites
+// loopback hosts when it fetches the admin settings.
+type redirectTransport struct {
+	base   http.RoundTripper
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = t.target.Scheme, t.target.Host
+	return t.base.RoundTrip(req)
+}
+
+func TestGetCatalogContextRecoversSDKPanic(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/adminSettings") {
+			w.Write([]byte(`{"data":{"settings":{"authMechanism":"local"}}}`))
+			return
+		}
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer srv.Close()
+	target, _ := url.Parse(srv.URL)
+
+	saved := http.DefaultTransport
+	http.DefaultTransport = redirectTransport{base: saved, target: target}
+	defer func() { http.DefaultTransport = saved }()
+
+	client, err := enbuild.NewClient(enbuild.WithBaseURL("https://enbuild.example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = getCatalogContext(context.Background(), client.Catalogs, "missing", &enbuild.CatalogListOptions{})
+	if err == nil || !strings.Contains(err.Error(), "ENBUILD SDK panic") {
+		t.Fatalf("Get of an empty data array returned %v, want an ENBUILD SDK panic error", err)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// describeFilters renders the non-empty search filters for a message.
+func describeFilters(vcs, name, match, catalogType string) string {
+	filters := []string{"vcs=" + vcs}
+	if name != "" {
+		filters = append(filters, fmt.Sprintf("name=%q (%s)", name, match))
+	}
+	if catalogType != "" {
+		filters = append(filters, "type="+catalogType)
+	}
+	return strings.Join(filters, ", ")
+}
+
+// catalogCount is count_catalogs' data. Count is always present, unlike
+// CatalogResponse's count, which is omitted when zero.
+type catalogCount struct {
+	Filters map[string]string `json:"filters"`
+	Count   int               `json:"count"`
+}
+
+func countCatalogs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	opts, problem, err := getSearchParams(request)
+	if err != nil {
+		return formatValidationErrorResponse(problem, err)
+	}
+	match, err := getMatchParam(request)
+	if err != nil {
+		return formatValidationErrorResponse("Invalid match value", err)
+	}
+
+	creds, err := getCredentials(request)
+	if err != nil {
+		return formatCredentialsErrorResponse(err)
+	}
+
+	client, err := initializeClient(ctx, creds)
+	if err != nil {
+		return formatErrorResponse("Failed to initialize ENBUILD client", err)
+	}
+
+	// The API has no count-only query, so the list is fetched and only its
+	// size is returned.
+	catalogs, warning, err := listFilteredCatalogs(ctx, client, opts)
+	if err != nil {
+		return formatCallErrorResponse("Failed to list catalogs", err)
+	}
+	catalogs = filterByName(catalogs, opts.Name, match)
+
+	response := CatalogResponse{
+		Success: true,
+		Count:   len(catalogs),
+		Total:   len(catalogs),
+		Data: catalogCount{
+			Filters: map[string]string{"vcs": opts.VCS, "name": opts.Name, "match": match, "type": opts.Type},
+			Count:   len(catalogs),
+		},
+		Message: fmt.Sprintf("%d catalogs match %s", len(catalogs), describeFilters(opts.VCS, opts.Name, match, opts.Type)) + warning,
+	}
+
+	return formatJSONResponse(response)
+}
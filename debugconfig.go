@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const redacted = "[redacted]"
+
+// startup records how the server was configured, for debug_config. main
+// fills in env before it sets any ENBUILD_* variables itself, and run
+// fills in the rest; under -self-test only env is set.
+var startup struct {
+	env              map[string]bool
+	flags            map[string]bool
+	configFile       string
+	transport        string
+	address          string
+	basePath         string
+	credentialSource string
+}
+
+func recordStartupEnvironment() {
+	startup.env = map[string]bool{}
+	for _, kv := range os.Environ() {
+		if name, value, _ := strings.Cut(kv, "="); value != "" {
+			startup.env[name] = true
+		}
+	}
+}
+
+func recordStartupConfig(transport, addr, basePath string, ec enbuildConfig) {
+	startup.flags = map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { startup.flags[f.Name] = true })
+	startup.configFile = ec.config
+	startup.transport = transport
+	startup.address = addr
+	startup.basePath = basePath
+	startup.credentialSource = ec.credentialSource()
+}
+
+type configSetting struct {
+	Value  interface{} `json:"value"`
+	Source string      `json:"source"`
+}
+
+// settingSource reports where a setting came from: its flag, its
+// environment variable, the --config file, or the built-in default.
+func settingSource(flagName, envVar string) string {
+	switch {
+	case flagName != "" && startup.flags[flagName]:
+		return "--" + flagName + " flag"
+	case envVar != "" && startup.env[envVar]:
+		return envVar + " environment variable"
+	case envVar != "" && os.Getenv(envVar) != "" && startup.configFile != "":
+		return "--config file " + startup.configFile
+	}
+	return "default"
+}
+
+func envSetting(flagName, envVar string) configSetting {
+	return configSetting{Value: os.Getenv(envVar), Source: settingSource(flagName, envVar)}
+}
+
+// credentialSetting reports a credential with the source of the startup
+// credentials, since flags, the secret manager, and the config file all
+// arrive through the same variables. Secrets only report whether they are
+// set.
+func credentialSetting(envVar string, secret bool) configSetting {
+	value := os.Getenv(envVar)
+	if value == "" {
+		return configSetting{Value: nil, Source: "not set"}
+	}
+	if secret {
+		return configSetting{Value: redacted, Source: startup.credentialSource}
+	}
+	return configSetting{Value: value, Source: startup.credentialSource}
+}
+
+// baseURLSetting reports the base URL, crediting --env or ENBUILD_ENV when
+// the URL came from a named environment.
+func baseURLSetting() configSetting {
+	setting := envSetting("base-url", "ENBUILD_BASE_URL")
+	switch {
+	case startup.flags["env"]:
+		setting.Source = "--env flag"
+	case setting.Source == "default" && startup.env["ENBUILD_ENV"]:
+		setting.Source = "ENBUILD_ENV environment variable"
+	}
+	return setting
+}
+
+// headerSetting reports the names of the custom headers; their values may
+// carry secrets.
+func headerSetting() configSetting {
+	setting := envSetting("header", "ENBUILD_HEADERS")
+	headers, err := parseHeaders(headerEntries(os.Getenv("ENBUILD_HEADERS")))
+	if err != nil || len(headers) == 0 {
+		setting.Value = nil
+		return setting
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	setting.Value = names
+	return setting
+}
+
+func traceEndpointSetting() configSetting {
+	envVar := "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	if os.Getenv(envVar) == "" {
+		envVar = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	}
+	setting := configSetting{Value: nil, Source: settingSource("", envVar)}
+	if endpoint := traceEndpoint(); endpoint != "" {
+		setting.Value = redacted
+		if u, err := url.Parse(endpoint); err == nil {
+			setting.Value = u.Redacted()
+		}
+	}
+	return setting
+}
+
+// debugConfig returns the effective configuration with secrets redacted.
+// registerTools adds it only when --log-level is debug. It does not depend
+// on --debug, which turns on the SDK's debug output; the SDK prints that to
+// stdout, unredacted, where it would corrupt the stdio transport.
+func debugConfig(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	auth := "none"
+	if creds, err := getCredentials(mcp.CallToolRequest{}); err == nil {
+		switch {
+		case creds.Token != "":
+			auth = "token"
+		case creds.ClientID != "":
+			auth = "client_credentials"
+		default:
+			auth = "username/password"
+		}
+	}
+
+	proxy := envSetting("proxy", "ENBUILD_PROXY")
+	if raw, _ := proxy.Value.(string); raw != "" {
+		if u, err := url.Parse(raw); err == nil {
+			proxy.Value = u.Redacted()
+		} else {
+			proxy.Value = redacted
+		}
+	}
+
+	backendSetting := configSetting{Value: backendNames(), Source: "default"}
+	if len(backends) > 0 {
+		backendSetting.Source = "--config file " + startup.configFile
+	}
+
+	settings := map[string]configSetting{
+		"base_url":       baseURLSetting(),
+		"transport":      {Value: startup.transport, Source: settingSource("transport", "")},
+		"address":        {Value: startup.address, Source: settingSource("sse-address", "")},
+		"base_path":      {Value: startup.basePath, Source: settingSource("base-path", "")},
+		"auth":           {Value: auth, Source: startup.credentialSource},
+		"username":       credentialSetting("ENBUILD_USERNAME", false),
+		"password":       credentialSetting("ENBUILD_PASSWORD", true),
+		"token":          credentialSetting("ENBUILD_TOKEN", true),
+		"client_id":      credentialSetting("ENBUILD_CLIENT_ID", false),
+		"client_secret":  credentialSetting("ENBUILD_CLIENT_SECRET", true),
+		"timeout":        envSetting("timeout", "ENBUILD_TIMEOUT"),
+		"max_retry_wait": {Value: maxRetryWait().String(), Source: settingSource("max-retry-wait", "ENBUILD_MAX_RETRY_WAIT")},
+		"idle_conns":     {Value: defaultHTTPTransport().MaxIdleConns, Source: settingSource("max-idle-conns", "ENBUILD_MAX_IDLE_CONNS")},
+		"idle_per_host":  {Value: maxIdleConnsPerHost(), Source: settingSource("max-idle-conns-per-host", "ENBUILD_MAX_IDLE_CONNS_PER_HOST")},
+		"idle_timeout":   {Value: defaultHTTPTransport().IdleConnTimeout.String(), Source: settingSource("idle-conn-timeout", "ENBUILD_IDLE_CONN_TIMEOUT")},
+		"proxy":          proxy,
+		"default_vcs":    envSetting("", "ENBUILD_DEFAULT_VCS"),
+		"read_only":      envSetting("read-only", "ENBUILD_READ_ONLY"),
+		"disabled_tools": envSetting("disable-tool", "ENBUILD_DISABLED_TOOLS"),
+		"insecure":       envSetting("insecure", "ENBUILD_INSECURE_SKIP_VERIFY"),
+		"token_cache":    envSetting("token-cache", "ENBUILD_TOKEN_CACHE"),
+		"headers":        headerSetting(),
+		"otlp_endpoint":  traceEndpointSetting(),
+		"audit_log":      envSetting("audit-log", "ENBUILD_AUDIT_LOG"),
+		"backends":       backendSetting,
+	}
+
+	response := CatalogResponse{
+		Success: true,
+		Count:   len(settings),
+		Data:    settings,
+		Message: "Effective configuration of ENBUILD MCP server " + serverVersion + "; secrets are redacted",
+	}
+
+	return formatJSONResponse(response)
+}
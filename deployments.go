@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+// defaultDeploymentLimit is how many records get_catalog_deployments
+// returns when no limit is given.
+const defaultDeploymentLimit = 20
+
+// deploymentContentKeys are the content keys deployment records are read
+// from. The SDK has no deployments API, so records a catalog embeds in its
+// content are the only ones this server can see.
+var deploymentContentKeys = []string{"deployments", "deployment_history"}
+
+type deploymentRecord struct {
+	Status    string      `json:"status,omitempty"`
+	Timestamp interface{} `json:"timestamp,omitempty"`
+	User      string      `json:"user,omitempty"`
+	Version   string      `json:"version,omitempty"`
+
+	time time.Time
+}
+
+// firstString returns the first of keys with a non-empty string value.
+func firstString(m map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if s, ok := m[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// catalogDeployments reads the deployment records in a catalog's content,
+// newest first. Records without a readable timestamp sort last.
+func catalogDeployments(catalog *enbuild.Catalog) []deploymentRecord {
+	var records []deploymentRecord
+	for _, key := range deploymentContentKeys {
+		items, _ := catalog.Content[key].([]interface{})
+		for _, item := range items {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			record := deploymentRecord{
+				Status:  firstString(m, "status", "state"),
+				User:    firstString(m, "user", "deployedBy", "deployed_by", "createdBy", "created_by"),
+				Version: firstString(m, "version"),
+			}
+			for _, key := range []string{"timestamp", "deployedAt", "deployed_at", "createdOn", "created_at", "date"} {
+				if v, ok := m[key]; ok && v != nil {
+					record.Timestamp = v
+					record.time, _ = parseCatalogTime(v)
+					break
+				}
+			}
+			records = append(records, record)
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].time.After(records[j].time)
+	})
+	return records
+}
+
+func getCatalogDeployments(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, ok := request.GetArguments()["id"].(string)
+	if !ok || id == "" {
+		return formatValidationErrorResponse("Missing required parameter", fmt.Errorf("catalog ID is required"))
+	}
+	limit, err := getIntArgument(request, "limit")
+	if err != nil {
+		return formatValidationErrorResponse("Invalid limit value", err)
+	}
+	if limit == 0 {
+		limit = defaultDeploymentLimit
+	}
+
+	creds, err := getCredentials(request)
+	if err != nil {
+		return formatCredentialsErrorResponse(err)
+	}
+
+	client, err := initializeClient(ctx, creds)
+	if err != nil {
+		return formatErrorResponse("Failed to initialize ENBUILD client", err)
+	}
+
+	catalog, err := getCatalogContext(ctx, client, id, &enbuild.CatalogListOptions{})
+	if err != nil {
+		return formatCallErrorResponse("Failed to get catalog", err)
+	}
+
+	records := catalogDeployments(catalog)
+	total := len(records)
+	if len(records) > limit {
+		records = records[:limit]
+	}
+
+	var message string
+	if total == 0 {
+		message = fmt.Sprintf("Catalog %s has no deployment records in its content. The ENBUILD SDK has no deployments API, so deployment history is not available", id)
+		if updated, ok := parseCatalogTime(catalog.UpdatedOn); ok {
+			message += fmt.Sprintf("; the catalog was last updated %s", updated.UTC().Format(time.RFC3339))
+		}
+	} else {
+		message = fmt.Sprintf("%d of %d deployment records for catalog %s, newest first. They are read from the catalog's content because the ENBUILD SDK has no deployments API, so they may be incomplete", len(records), total, id)
+	}
+	response := CatalogResponse{
+		Success: true,
+		Count:   len(records),
+		Total:   total,
+		Data:    append([]deploymentRecord{}, records...),
+		Message: message,
+	}
+
+	return formatJSONResponse(response)
+}
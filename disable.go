@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// stringListFlag collects the values of a flag that may be repeated, such
+// as --disable-tool a --disable-tool b.
+type stringListFlag []string
+
+func (f *stringListFlag) String() string { return strings.Join(*f, ",") }
+
+func (f *stringListFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// disabledToolNames parses ENBUILD_DISABLED_TOOLS, a comma-separated list of
+// tool names.
+func disabledToolNames() map[string]bool {
+	names := map[string]bool{}
+	for _, name := range strings.Split(os.Getenv("ENBUILD_DISABLED_TOOLS"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// filterDisabled drops the tools named in ENBUILD_DISABLED_TOOLS, so they are
+// never listed or callable. Names that match no tool are logged rather than
+// treated as errors, so a list shared across versions keeps working.
+func filterDisabled(tools []server.ServerTool) []server.ServerTool {
+	disabled := disabledToolNames()
+	if len(disabled) == 0 {
+		return tools
+	}
+	kept := tools[:0]
+	for _, tool := range tools {
+		if disabled[tool.Tool.Name] {
+			infof("Tool %s is disabled; not registering it", tool.Tool.Name)
+			delete(disabled, tool.Tool.Name)
+			continue
+		}
+		kept = append(kept, tool)
+	}
+	unknown := make([]string, 0, len(disabled))
+	for name := range disabled {
+		unknown = append(unknown, name)
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		warnf("Cannot disable unknown tool: %s", name)
+	}
+	return kept
+}
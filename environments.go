@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// defaultBaseURL is the ENBUILD used when no base URL or environment is
+// given.
+const defaultBaseURL = "https://enbuild.vivplatform.io"
+
+// builtinEnvironments are the --env shortcuts available without a config
+// file. The config file's environments map adds to and overrides them, and
+// is where staging and other deployments are defined.
+var builtinEnvironments = map[string]string{
+	"prod": defaultBaseURL,
+}
+
+// resolveEnvironment returns the base URL of a named environment, matching
+// names case-insensitively. overrides come from the config file.
+func resolveEnvironment(name string, overrides map[string]string) (string, error) {
+	environments := map[string]string{}
+	for env, baseURL := range builtinEnvironments {
+		environments[env] = baseURL
+	}
+	for env, baseURL := range overrides {
+		environments[strings.ToLower(env)] = baseURL
+	}
+
+	if baseURL, ok := environments[strings.ToLower(strings.TrimSpace(name))]; ok && baseURL != "" {
+		return baseURL, nil
+	}
+	names := make([]string, 0, len(environments))
+	for env := range environments {
+		names = append(names, env)
+	}
+	sort.Strings(names)
+	return "", fmt.Errorf("unknown environment %q: must be one of %s, or add it under environments in the --config file", name, strings.Join(names, ", "))
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Error types reported in CatalogResponse.ErrorType so agents can branch on
+// the kind of failure without parsing the message.
+const (
+	errorTypeAuth        = "auth"
+	errorTypeNotFound    = "not_found"
+	errorTypeValidation  = "validation"
+	errorTypeNetwork     = "network"
+	errorTypeRateLimited = "rate_limited"
+	errorTypeInternal    = "internal"
+)
+
+// inferErrorType classifies a failed ENBUILD call from the error alone, for
+// callers that do not know the kind of failure themselves.
+func inferErrorType(err error) string {
+	if err == nil {
+		return errorTypeInternal
+	}
+	switch statusCodeFromError(err) {
+	case 401, 403:
+		return errorTypeAuth
+	case 404:
+		return errorTypeNotFound
+	case 429:
+		return errorTypeRateLimited
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errorTypeNetwork
+	}
+	switch classifyPingError(err) {
+	case errorCodeAuthFailed:
+		return errorTypeAuth
+	case errorCodeConnectionFailed:
+		return errorTypeNetwork
+	}
+	if strings.Contains(strings.ToLower(err.Error()), "not found") {
+		return errorTypeNotFound
+	}
+	return errorTypeInternal
+}
+
+// formatValidationErrorResponse reports an invalid or missing tool argument.
+func formatValidationErrorResponse(message string, err error) (*mcp.CallToolResult, error) {
+	return formatTypedErrorResponse(errorTypeValidation, "", message, err)
+}
+
+// formatCredentialsErrorResponse reports credentials that getCredentials
+// could not resolve.
+func formatCredentialsErrorResponse(err error) (*mcp.CallToolResult, error) {
+	return formatTypedErrorResponse(errorTypeAuth, "", "Invalid credentials", err)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// bytesPerToken is a rough average for JSON text with most tokenizers.
+	bytesPerToken = 4
+	// largeResponseTokens is the size above which paging is suggested.
+	largeResponseTokens = 8000
+)
+
+type searchCostEstimate struct {
+	Filters         map[string]string `json:"filters"`
+	Count           int               `json:"count"`
+	EstimatedBytes  int               `json:"estimated_bytes"`
+	EstimatedTokens int               `json:"estimated_tokens"`
+	Large           bool              `json:"large"`
+	SuggestedLimit  int               `json:"suggested_limit,omitempty"`
+	Suggestion      string            `json:"suggestion,omitempty"`
+}
+
+func estimateSearchCost(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	opts, problem, err := getSearchParams(request)
+	if err != nil {
+		return formatValidationErrorResponse(problem, err)
+	}
+	match, err := getMatchParam(request)
+	if err != nil {
+		return formatValidationErrorResponse("Invalid match value", err)
+	}
+
+	creds, err := getCredentials(request)
+	if err != nil {
+		return formatCredentialsErrorResponse(err)
+	}
+
+	client, err := initializeClient(ctx, creds)
+	if err != nil {
+		return formatErrorResponse("Failed to initialize ENBUILD client", err)
+	}
+
+	// The API has no count-only query, so the list is fetched here but only
+	// its size is returned to the caller. The name is filtered as
+	// search_catalogs filters it, so the estimate matches its result.
+	catalogs, warning, err := listFilteredCatalogs(ctx, client, opts)
+	if err != nil {
+		return formatCallErrorResponse("Failed to list catalogs", err)
+	}
+	catalogs = filterByName(catalogs, opts.Name, match)
+
+	found := len(catalogs) > 0
+	payload, err := json.MarshalIndent(CatalogResponse{
+		APIVersion: responseAPIVersion,
+		Success:    true,
+		Count:      len(catalogs),
+		Found:      &found,
+		Data:       withWebURLs(creds.BaseURL, catalogs),
+		Message:    fmt.Sprintf("Successfully retrieved %d catalogs for VCS: %s", len(catalogs), opts.VCS),
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error estimating response size: %v", err)
+	}
+
+	estimate := searchCostEstimate{
+		Filters:         map[string]string{"vcs": opts.VCS, "name": opts.Name, "match": match, "type": opts.Type},
+		Count:           len(catalogs),
+		EstimatedBytes:  len(payload),
+		EstimatedTokens: (len(payload) + bytesPerToken - 1) / bytesPerToken,
+	}
+	if estimate.EstimatedTokens > largeResponseTokens && len(catalogs) > 0 {
+		perCatalog := estimate.EstimatedTokens / len(catalogs)
+		estimate.Large = true
+		estimate.SuggestedLimit = max(largeResponseTokens/max(perCatalog, 1), 1)
+		estimate.Suggestion = fmt.Sprintf("The full result is large; call search_catalogs with limit=%d and follow resume_token, narrow the name/type filters, or use max_field_length to shorten long fields.", estimate.SuggestedLimit)
+	}
+
+	response := CatalogResponse{
+		Success: true,
+		Count:   estimate.Count,
+		Data:    estimate,
+		Message: fmt.Sprintf("search_catalogs would return %d catalogs, about %d bytes (~%d tokens)", estimate.Count, estimate.EstimatedBytes, estimate.EstimatedTokens) + warning,
+	}
+
+	return formatJSONResponse(response)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+// fieldsTools are the tools whose data is a catalog or a list of catalogs,
+// and so accept the fields argument.
+var fieldsTools = map[string]bool{
+	"search_catalogs":        true,
+	"search_catalogs_regex":  true,
+	"get_catalog_details":    true,
+	"get_catalogs":           true,
+	"list_catalogs_by_vcs":   true,
+	"catalog_freshness":      true,
+	"list_writable_catalogs": true,
+}
+
+// catalogFieldNames returns the JSON names of the fields a catalog result
+// can have, including those added by the tools.
+func catalogFieldNames() map[string]bool {
+	names := map[string]bool{"web_url": true, "source_vcs": true}
+	t := reflect.TypeOf(enbuild.Catalog{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// getFieldsParam parses the comma-separated fields argument. "id" is
+// accepted for the catalog's "_id" field.
+func getFieldsParam(request mcp.CallToolRequest) []string {
+	raw, _ := request.GetArguments()["fields"].(string)
+	var fields []string
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "id" {
+			field = "_id"
+		}
+		if field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// projectFields keeps only the wanted keys of each catalog object in data,
+// which is a single object or a list of objects. Entries that wrap the
+// catalog in a "catalog" key, as catalog_freshness does, keep their other
+// keys and have the wrapped catalog projected.
+func projectFields(data interface{}, wanted map[string]bool) interface{} {
+	switch t := data.(type) {
+	case map[string]interface{}:
+		if catalog, ok := t["catalog"]; ok {
+			projectFields(catalog, wanted)
+			break
+		}
+		for k := range t {
+			if !wanted[k] {
+				delete(t, k)
+			}
+		}
+	case []interface{}:
+		for _, item := range t {
+			projectFields(item, wanted)
+		}
+	}
+	return data
+}
+
+// projectFieldsMiddleware trims catalog results down to the fields named in
+// the per-call fields argument. Unknown names are ignored and listed in the
+// message.
+func projectFieldsMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, request)
+		fields := getFieldsParam(request)
+		if err != nil || result == nil || result.IsError || len(fields) == 0 || !fieldsTools[request.Params.Name] || len(result.Content) != 1 {
+			return result, err
+		}
+		text, ok := result.Content[0].(mcp.TextContent)
+		if !ok {
+			return result, nil
+		}
+
+		var response CatalogResponse
+		decoder := json.NewDecoder(strings.NewReader(text.Text))
+		decoder.UseNumber()
+		if err := decoder.Decode(&response); err != nil || response.Data == nil {
+			return result, nil
+		}
+
+		known := catalogFieldNames()
+		wanted := map[string]bool{}
+		var unknown []string
+		for _, field := range fields {
+			if known[field] {
+				wanted[field] = true
+			} else {
+				unknown = append(unknown, field)
+			}
+		}
+		response.Data = projectFields(response.Data, wanted)
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			response.Message += fmt.Sprintf(" (ignored unknown fields: %s)", strings.Join(unknown, ", "))
+		}
+
+		return formatJSONResponse(response)
+	}
+}
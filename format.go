@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"gopkg.in/yaml.v3"
+)
+
+func formatYAMLResponse(response CatalogResponse) (*mcp.CallToolResult, error) {
+	response.APIVersion = responseAPIVersion
+	yamlData, err := yaml.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("error formatting YAML response: %v", err)
+	}
+
+	return mcp.NewToolResultText(string(yamlData)), nil
+}
+
+// yamlValue converts the json.Number values left by a UseNumber decode into
+// integers or floats so YAML renders them as numbers rather than strings.
+func yamlValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		if n, err := t.Int64(); err == nil {
+			return n
+		}
+		if f, err := t.Float64(); err == nil {
+			return f
+		}
+		return t.String()
+	case map[string]interface{}:
+		for k, item := range t {
+			t[k] = yamlValue(item)
+		}
+		return t
+	case []interface{}:
+		for i, item := range t {
+			t[i] = yamlValue(item)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// outputFormatMiddleware re-encodes tool responses according to the per-call
+// format argument. It runs outside truncateFieldsMiddleware, which expects
+// JSON from the handlers.
+func outputFormatMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, request)
+		format, _ := request.GetArguments()["format"].(string)
+		format = strings.ToLower(strings.TrimSpace(format))
+		if err != nil || result == nil || format == "" || format == "json" || len(result.Content) != 1 {
+			return result, err
+		}
+		text, ok := result.Content[0].(mcp.TextContent)
+		if !ok {
+			return result, nil
+		}
+
+		var response CatalogResponse
+		decoder := json.NewDecoder(strings.NewReader(text.Text))
+		decoder.UseNumber()
+		if err := decoder.Decode(&response); err != nil {
+			return result, nil
+		}
+
+		var formatted *mcp.CallToolResult
+		if format == "yaml" {
+			response.Data = yamlValue(response.Data)
+			formatted, err = formatYAMLResponse(response)
+		} else {
+			response.Message += fmt.Sprintf(" (unknown format %q, using json)", format)
+			formatted, err = formatJSONResponse(response)
+		}
+		if err != nil {
+			return nil, err
+		}
+		formatted.IsError = result.IsError
+		return formatted, nil
+	}
+}
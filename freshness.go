@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+const defaultFreshnessWindow = 180 * 24 * time.Hour
+
+type freshnessEntry struct {
+	Catalog        *enbuild.Catalog `json:"catalog"`
+	FreshnessScore *float64         `json:"freshness_score"`
+	LastUpdated    string           `json:"last_updated,omitempty"`
+	AgeDays        *float64         `json:"age_days,omitempty"`
+}
+
+func freshnessWindow() time.Duration {
+	if v := os.Getenv("ENBUILD_FRESHNESS_WINDOW"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultFreshnessWindow
+}
+
+// parseCatalogTime accepts the timestamp shapes the API is known to return:
+// RFC 3339 strings and Unix epoch milliseconds.
+func parseCatalogTime(v interface{}) (time.Time, bool) {
+	switch t := v.(type) {
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, time.RFC3339, "2006-01-02"} {
+			if parsed, err := time.Parse(layout, t); err == nil {
+				return parsed, true
+			}
+		}
+	case float64:
+		if t > 0 {
+			return time.UnixMilli(int64(t)), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// freshnessScore maps the age of a catalog onto [0, 1]: 1 for a catalog
+// updated just now, decaying linearly to 0 at the end of the window.
+func freshnessScore(updated, now time.Time, window time.Duration) float64 {
+	age := now.Sub(updated)
+	if age <= 0 {
+		return 1
+	}
+	score := 1 - float64(age)/float64(window)
+	return math.Round(math.Max(score, 0)*1000) / 1000
+}
+
+func annotateFreshness(catalogs []*enbuild.Catalog, now time.Time, window time.Duration) ([]freshnessEntry, int) {
+	annotated := make([]freshnessEntry, 0, len(catalogs))
+	missing := 0
+	for _, c := range catalogs {
+		entry := freshnessEntry{Catalog: c}
+		updated, ok := parseCatalogTime(c.UpdatedOn)
+		if !ok {
+			updated, ok = parseCatalogTime(c.CreatedOn)
+		}
+		if ok {
+			score := freshnessScore(updated, now, window)
+			ageDays := math.Round(now.Sub(updated).Hours()/24*10) / 10
+			entry.FreshnessScore = &score
+			entry.AgeDays = &ageDays
+			entry.LastUpdated = updated.UTC().Format(time.RFC3339)
+		} else {
+			missing++
+		}
+		annotated = append(annotated, entry)
+	}
+
+	sort.SliceStable(annotated, func(i, j int) bool {
+		a, b := annotated[i].FreshnessScore, annotated[j].FreshnessScore
+		if a == nil || b == nil {
+			return b == nil && a != nil
+		}
+		return *a > *b
+	})
+	return annotated, missing
+}
+
+func catalogFreshness(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	opts, problem, err := getSearchParams(request)
+	if err != nil {
+		return formatValidationErrorResponse(problem, err)
+	}
+
+	creds, err := getCredentials(request)
+	if err != nil {
+		return formatCredentialsErrorResponse(err)
+	}
+
+	client, err := initializeClient(ctx, creds)
+	if err != nil {
+		return formatErrorResponse("Failed to initialize ENBUILD client", err)
+	}
+
+	catalogs, warning, err := listFilteredCatalogs(ctx, client, opts)
+	if err != nil {
+		return formatCallErrorResponse("Failed to list catalogs", err)
+	}
+
+	window := freshnessWindow()
+	annotated, missing := annotateFreshness(catalogs, time.Now(), window)
+
+	message := fmt.Sprintf("Scored freshness of %d catalogs for VCS: %s over a %s window", len(annotated), opts.VCS, window)
+	if missing > 0 {
+		message += fmt.Sprintf("; warning: %d catalogs have no timestamp and were given a null freshness_score", missing)
+	}
+	message += warning
+
+	response := CatalogResponse{
+		Success: true,
+		Count:   len(annotated),
+		Data:    annotated,
+		Message: message,
+	}
+
+	return formatJSONResponse(response)
+}
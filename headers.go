@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+// reservedHeaders may not be set with --header because the clients manage
+// them and overriding them would break requests.
+var reservedHeaders = map[string]bool{
+	"Authorization":  true,
+	"Host":           true,
+	"Content-Length": true,
+	"Content-Type":   true,
+}
+
+// headerEntries splits ENBUILD_HEADERS into "Key=Value" entries. A value
+// with a line break lists one entry per line, which is how --header values
+// are passed on. Otherwise entries are separated by commas, and a piece
+// without "=" continues the previous value, so "X-Scope=a,b" stays whole.
+func headerEntries(raw string) []string {
+	if strings.Contains(raw, "\n") {
+		return strings.Split(raw, "\n")
+	}
+	var entries []string
+	for _, piece := range strings.Split(raw, ",") {
+		if len(entries) > 0 && !strings.Contains(piece, "=") {
+			entries[len(entries)-1] += "," + piece
+			continue
+		}
+		entries = append(entries, piece)
+	}
+	return entries
+}
+
+// parseHeaders parses "Key=Value" pairs from --header flags and
+// ENBUILD_HEADERS (see headerEntries).
+func parseHeaders(pairs []string) (http.Header, error) {
+	headers := http.Header{}
+	for _, pair := range pairs {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid header %q: must be key=value", pair)
+		}
+		if strings.ContainsAny(key, " \t:\r\n") || strings.ContainsAny(value, "\r\n") {
+			return nil, fmt.Errorf("invalid header %q: names may not contain spaces or colons, and values may not contain line breaks", pair)
+		}
+		key = http.CanonicalHeaderKey(key)
+		if reservedHeaders[key] {
+			return nil, fmt.Errorf("header %s cannot be overridden with --header", key)
+		}
+		headers.Set(key, strings.TrimSpace(value))
+	}
+	return headers, nil
+}
+
+// enbuildTransport wraps http.DefaultTransport. It adds the custom headers
+// to requests bound for the startup base URL's host, so they reach the
+// catalog API and admin settings but not Keycloak, repository checks, or
+// the hosts of per-call base_url arguments and configured backends, and
+// retries throttled requests to any host (see retryRateLimited).
+type enbuildTransport struct {
+	base    *http.Transport
+	host    string
+	headers http.Header
+	// random is the jitter source for retries; nil uses math/rand/v2.
+	random func() float64
+}
+
+// newEnbuildTransport returns a transport over base for the ENBUILD host.
+// random is passed to retryDelay; pass nil outside tests.
+func newEnbuildTransport(base *http.Transport, host string, headers http.Header, random func() float64) enbuildTransport {
+	return enbuildTransport{base: base, host: host, headers: headers, random: random}
+}
+
+func (t enbuildTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(t.headers) > 0 && strings.EqualFold(req.URL.Host, t.host) {
+		req = req.Clone(req.Context())
+		for key, values := range t.headers {
+			req.Header[key] = values
+		}
+	}
+	return retryRateLimited(t.base, req, t.random)
+}
+
+// defaultHTTPTransport returns the *http.Transport behind
+// http.DefaultTransport, looking through enbuildTransport.
+func defaultHTTPTransport() *http.Transport {
+	if t, ok := http.DefaultTransport.(enbuildTransport); ok {
+		return t.base
+	}
+	return http.DefaultTransport.(*http.Transport)
+}
+
+// configureTransport installs enbuildTransport, sending the headers in
+// ENBUILD_HEADERS on every request to the ENBUILD host. The SDK's clients
+// have no transport of their own and its options cannot reach them, so
+// http.DefaultTransport is wrapped, as --proxy and --insecure change it in
+// place.
+func configureTransport(baseURL string) error {
+	headers, err := parseHeaders(headerEntries(os.Getenv("ENBUILD_HEADERS")))
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL: %v", err)
+	}
+	http.DefaultTransport = newEnbuildTransport(defaultHTTPTransport(), u.Host, headers, nil)
+	if len(headers) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	debugf("Sending custom headers to %s: %s", u.Host, strings.Join(names, ", "))
+	return nil
+}
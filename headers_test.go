@@ -0,0 +1,24 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseHeadersKeepsCommasInValues(t *testing.T) {
+	for _, raw := range []string{
+		"X-Scope=a,b,X-Tenant=t1",
+		strings.Join([]string{"X-Scope=a,b", "X-Tenant=t1"}, "\n") + "\n",
+	} {
+		headers, err := parseHeaders(headerEntries(raw))
+		if err != nil {
+			t.Fatalf("%q: %v", raw, err)
+		}
+		if got := headers.Get("X-Scope"); got != "a,b" {
+			t.Errorf("%q: X-Scope = %q, want a,b", raw, got)
+		}
+		if got := headers.Get("X-Tenant"); got != "t1" {
+			t.Errorf("%q: X-Tenant = %q, want t1", raw, got)
+		}
+	}
+}
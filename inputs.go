@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+type catalogInput struct {
+	Name        string      `json:"name"`
+	Type        string      `json:"type,omitempty"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description,omitempty"`
+}
+
+type inputResult struct {
+	Name     string `json:"name"`
+	Type     string `json:"type,omitempty"`
+	Required bool   `json:"required"`
+	Present  bool   `json:"present"`
+	Valid    bool   `json:"valid"`
+	Message  string `json:"message,omitempty"`
+}
+
+type inputValidationReport struct {
+	Valid           bool          `json:"valid"`
+	Inputs          []inputResult `json:"inputs"`
+	MissingRequired []string      `json:"missing_required,omitempty"`
+	UnknownKeys     []string      `json:"unknown_keys,omitempty"`
+}
+
+// catalogInputs extracts the declared inputs from a catalog's content. The
+// schema may be stored under "inputs" or "variables", either as a list of
+// input objects or as a map keyed by input name.
+func catalogInputs(catalog *enbuild.Catalog) []catalogInput {
+	var raw interface{}
+	for _, key := range []string{"inputs", "variables"} {
+		if v, ok := catalog.Content[key]; ok {
+			raw = v
+			break
+		}
+	}
+
+	var inputs []catalogInput
+	switch v := raw.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if m, ok := item.(map[string]interface{}); ok {
+				name, _ := m["name"].(string)
+				inputs = append(inputs, newCatalogInput(name, m))
+			}
+		}
+	case map[string]interface{}:
+		for name, item := range v {
+			m, _ := item.(map[string]interface{})
+			inputs = append(inputs, newCatalogInput(name, m))
+		}
+		sort.Slice(inputs, func(i, j int) bool { return inputs[i].Name < inputs[j].Name })
+	}
+	return inputs
+}
+
+func newCatalogInput(name string, m map[string]interface{}) catalogInput {
+	input := catalogInput{Name: name}
+	if m == nil {
+		return input
+	}
+	input.Type, _ = m["type"].(string)
+	input.Description, _ = m["description"].(string)
+	input.Default = m["default"]
+	input.Required, _ = m["required"].(bool)
+	if _, hasDefault := m["default"]; !hasDefault {
+		if _, declared := m["required"]; !declared {
+			// Terraform semantics: a variable without a default must be set.
+			input.Required = true
+		}
+	}
+	return input
+}
+
+func matchesInputType(inputType string, value interface{}) bool {
+	switch strings.ToLower(inputType) {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number", "int", "integer", "float":
+		_, ok := value.(float64)
+		return ok
+	case "bool", "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "list", "array", "set", "tuple":
+		_, ok := value.([]interface{})
+		return ok
+	case "map", "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	default:
+		// Unknown or complex type expressions are not checked.
+		return true
+	}
+}
+
+func validateInputs(declared []catalogInput, provided map[string]interface{}) inputValidationReport {
+	report := inputValidationReport{Valid: true, Inputs: []inputResult{}}
+	known := make(map[string]bool, len(declared))
+
+	for _, input := range declared {
+		known[input.Name] = true
+		result := inputResult{Name: input.Name, Type: input.Type, Required: input.Required}
+
+		value, present := provided[input.Name]
+		result.Present = present && value != nil
+		switch {
+		case !result.Present && input.Required:
+			result.Message = "required input is missing"
+			report.MissingRequired = append(report.MissingRequired, input.Name)
+		case !result.Present:
+			result.Valid = true
+			result.Message = "not provided, default will be used"
+		case !matchesInputType(input.Type, value):
+			result.Message = fmt.Sprintf("expected a value of type %s", input.Type)
+		default:
+			result.Valid = true
+		}
+
+		if !result.Valid {
+			report.Valid = false
+		}
+		report.Inputs = append(report.Inputs, result)
+	}
+
+	for key := range provided {
+		if !known[key] {
+			report.UnknownKeys = append(report.UnknownKeys, key)
+		}
+	}
+	sort.Strings(report.UnknownKeys)
+	if len(report.UnknownKeys) > 0 {
+		report.Valid = false
+	}
+
+	return report
+}
+
+func getInputsArgument(request mcp.CallToolRequest) (map[string]interface{}, error) {
+	switch v := request.GetArguments()["inputs"].(type) {
+	case nil:
+		return map[string]interface{}{}, nil
+	case map[string]interface{}:
+		return v, nil
+	case string:
+		inputs := map[string]interface{}{}
+		if strings.TrimSpace(v) == "" {
+			return inputs, nil
+		}
+		if err := json.Unmarshal([]byte(v), &inputs); err != nil {
+			return nil, fmt.Errorf("inputs must be a JSON object: %v", err)
+		}
+		return inputs, nil
+	default:
+		return nil, fmt.Errorf("inputs must be a JSON object")
+	}
+}
+
+// checkCatalogVersion fails when version is set and the catalog is at a
+// different one. ENBUILD only serves a catalog's current version and ignores
+// the SDK's Version option on Get, so the version argument can only be
+// checked, not fetched.
+func checkCatalogVersion(catalog *enbuild.Catalog, id, version string) error {
+	if version == "" || strings.EqualFold(catalog.Version, version) {
+		return nil
+	}
+	return fmt.Errorf("catalog %s is at version %q, not %q; only a catalog's current version can be read", id, catalog.Version, version)
+}
+
+func getCatalogInputs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, ok := request.GetArguments()["id"].(string)
+	if !ok || id == "" {
+		return formatValidationErrorResponse("Missing required parameter", fmt.Errorf("catalog ID is required"))
+	}
+	version, _ := request.GetArguments()["version"].(string)
+
+	creds, err := getCredentials(request)
+	if err != nil {
+		return formatCredentialsErrorResponse(err)
+	}
+
+	client, err := initializeClient(ctx, creds)
+	if err != nil {
+		return formatErrorResponse("Failed to initialize ENBUILD client", err)
+	}
+
+	catalog, err := getCatalogContext(ctx, client, id, &enbuild.CatalogListOptions{})
+	if err != nil {
+		return formatCallErrorResponse("Failed to get catalog details", err)
+	}
+	if err := checkCatalogVersion(catalog, id, version); err != nil {
+		return formatTypedErrorResponse(errorTypeNotFound, "", "Catalog version not available", err)
+	}
+
+	// The SDK's Catalog has no inputs field, so they come from its content.
+	inputs := catalogInputs(catalog)
+	if inputs == nil {
+		inputs = []catalogInput{}
+	}
+
+	message := fmt.Sprintf("Catalog ID %s declares %d inputs", id, len(inputs))
+	if len(inputs) == 0 {
+		message = fmt.Sprintf("Catalog ID %s declares no inputs", id)
+	}
+
+	response := CatalogResponse{
+		Success: true,
+		Count:   len(inputs),
+		Data:    inputs,
+		Message: message,
+	}
+
+	return formatJSONResponse(response)
+}
+
+func validateCatalogInputs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, ok := request.GetArguments()["id"].(string)
+	if !ok || id == "" {
+		return formatValidationErrorResponse("Missing required parameter", fmt.Errorf("catalog ID is required"))
+	}
+	version, _ := request.GetArguments()["version"].(string)
+
+	inputs, err := getInputsArgument(request)
+	if err != nil {
+		return formatValidationErrorResponse("Invalid inputs parameter", err)
+	}
+
+	creds, err := getCredentials(request)
+	if err != nil {
+		return formatCredentialsErrorResponse(err)
+	}
+
+	client, err := initializeClient(ctx, creds)
+	if err != nil {
+		return formatErrorResponse("Failed to initialize ENBUILD client", err)
+	}
+
+	catalog, err := getCatalogContext(ctx, client, id, &enbuild.CatalogListOptions{})
+	if err != nil {
+		return formatCallErrorResponse("Failed to get catalog details", err)
+	}
+	if err := checkCatalogVersion(catalog, id, version); err != nil {
+		return formatTypedErrorResponse(errorTypeNotFound, "", "Catalog version not available", err)
+	}
+
+	declared := catalogInputs(catalog)
+	report := validateInputs(declared, inputs)
+
+	message := fmt.Sprintf("Inputs are valid for catalog ID: %s", id)
+	if !report.Valid {
+		message = fmt.Sprintf("Inputs failed validation for catalog ID: %s", id)
+	}
+	switch {
+	case len(declared) == 0 && len(report.UnknownKeys) > 0:
+		message = fmt.Sprintf("Inputs failed validation for catalog ID: %s; it declares no inputs, so the provided keys are unknown: %s", id, strings.Join(report.UnknownKeys, ", "))
+	case len(declared) == 0:
+		message = fmt.Sprintf("Catalog ID %s declares no inputs", id)
+	}
+
+	response := CatalogResponse{
+		Success: true,
+		Count:   len(report.Inputs),
+		Data:    report,
+		Message: message,
+	}
+
+	return formatJSONResponse(response)
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+// tokenRefreshMargin is how long before expiry a Keycloak token is
+// replaced, so a request never goes out with a token about to lapse.
+const tokenRefreshMargin = 30 * time.Second
+
+// localAuthToken is the token ENBUILD accepts when its admin settings use
+// local rather than Keycloak authentication, as in the SDK.
+const localAuthToken = "enbuild_local_admin_token"
+
+// keycloakTokenSource obtains bearer tokens from the Keycloak realm that
+// ENBUILD's admin settings point at, using the grant built by grant. The
+// SDK implements only the password grant and keeps its token private, so
+// this mirrors its discovery for the client-credentials grant and for
+// password logins whose token is persisted to disk.
+type keycloakTokenSource struct {
+	baseURL string
+	// grant returns the token request form; uiClientID is the Keycloak
+	// client the ENBUILD UI logs in with.
+	grant func(uiClientID string) url.Values
+	// diskKey names the token in the on-disk cache; "" keeps it in memory.
+	diskKey    string
+	httpClient *http.Client
+
+	mu         sync.Mutex
+	discovered bool
+	local      bool
+	tokenURL   string
+	uiClientID string
+	token      string
+	expiresAt  time.Time
+}
+
+func newKeycloakTokenSource(baseURL, diskKey string, grant func(uiClientID string) url.Values) *keycloakTokenSource {
+	timeout := defaultRequestTimeout
+	if d, err := time.ParseDuration(os.Getenv("ENBUILD_TIMEOUT")); err == nil && d > 0 {
+		timeout = d
+	}
+	return &keycloakTokenSource{
+		baseURL:    baseURL,
+		grant:      grant,
+		diskKey:    diskKey,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Token returns a cached access token, requesting a new one once the
+// current token is within tokenRefreshMargin of expiring. ctx bounds the
+// discovery and token requests.
+func (s *keycloakTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Now().Before(s.expiresAt) {
+		return s.token, nil
+	}
+	if s.diskKey != "" {
+		if token, expiresAt, ok := loadCachedToken(s.diskKey); ok {
+			s.token, s.expiresAt = token, expiresAt
+			debugf("Reusing the Keycloak token cached on disk, valid until %s", expiresAt.Format(time.RFC3339))
+			return s.token, nil
+		}
+	}
+	if !s.discovered {
+		if err := s.discover(ctx); err != nil {
+			return "", err
+		}
+		s.discovered = true
+	}
+	if s.local {
+		return localAuthToken, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURL, strings.NewReader(s.grant(s.uiClientID).Encode()))
+	if err != nil {
+		return "", fmt.Errorf("authentication with Keycloak failed: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("authentication with Keycloak failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("authentication with Keycloak failed: API error: %s", resp.Status)
+	}
+	var token enbuild.KeycloakTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("authentication with Keycloak failed: invalid token response: %v", err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("authentication with Keycloak failed: no access token in response")
+	}
+
+	s.token = token.AccessToken
+	s.expiresAt = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - tokenRefreshMargin)
+	debugf("Obtained Keycloak token, expires in %ds", token.ExpiresIn)
+	if s.diskKey != "" {
+		if err := saveCachedToken(s.diskKey, s.token, s.expiresAt); err != nil {
+			warnf("Could not write the token cache: %v", err)
+		}
+	}
+	return s.token, nil
+}
+
+// discover reads the authentication mechanism, Keycloak backend, realm, and
+// UI client from ENBUILD's admin settings.
+func (s *keycloakTokenSource) discover(ctx context.Context) error {
+	parsed, err := url.Parse(s.baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL: %v", err)
+	}
+	settingsURL := fmt.Sprintf("%s://%s/enbuild-user/api/v1/adminSettings", parsed.Scheme, parsed.Host)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, settingsURL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid base URL: %v", err)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Failed to fetch authMechanism from ENBUILD. Please check ENBUILD_BASE_URL or network connectivity: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Failed to fetch authMechanism from ENBUILD: API error: %s", resp.Status)
+	}
+	var settings enbuild.AdminSettingsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&settings); err != nil {
+		return fmt.Errorf("failed to parse admin settings: %v", err)
+	}
+
+	for _, setting := range settings.Data {
+		if setting.AuthMechanism == "local" {
+			s.local = true
+			return nil
+		}
+		keycloak := setting.AdminConfigs.Keycloak
+		if setting.AuthMechanism != "keycloak" || keycloak.KeycloakBackendURL == "" || keycloak.KeycloakRealm == "" {
+			continue
+		}
+		backend := strings.TrimRight(keycloak.KeycloakBackendURL, "/")
+		if !strings.HasPrefix(backend, "http://") && !strings.HasPrefix(backend, "https://") {
+			backend = "https://" + backend
+		}
+		s.tokenURL = fmt.Sprintf("%s/realms/%s/protocol/openid-connect/token", backend, url.PathEscape(keycloak.KeycloakRealm))
+		s.uiClientID = keycloak.KeycloakClientID
+		return nil
+	}
+	return fmt.Errorf("no valid authentication configuration found in the admin settings of ENBUILD at %s", s.baseURL)
+}
+
+// newClientCredentialsCatalogService returns a catalog service that
+// authenticates as a Keycloak client rather than a user.
+func newClientCredentialsCatalogService(baseURL, clientID, clientSecret, diskKey string) (*tokenCatalogService, error) {
+	source := newKeycloakTokenSource(baseURL, diskKey, func(string) url.Values {
+		return url.Values{
+			"grant_type":    {"client_credentials"},
+			"client_id":     {clientID},
+			"client_secret": {clientSecret},
+		}
+	})
+	return newTokenSourceCatalogService(baseURL, source.Token)
+}
+
+// newPasswordCatalogService returns a catalog service that logs in with a
+// username and password like the SDK, but through a token source whose
+// token can be cached on disk.
+func newPasswordCatalogService(baseURL, username, password, diskKey string) (*tokenCatalogService, error) {
+	source := newKeycloakTokenSource(baseURL, diskKey, func(uiClientID string) url.Values {
+		return url.Values{
+			"grant_type": {"password"},
+			"client_id":  {uiClientID},
+			"username":   {username},
+			"password":   {password},
+		}
+	})
+	return newTokenSourceCatalogService(baseURL, source.Token)
+}
+
+func newTokenSourceCatalogService(baseURL string, token func(context.Context) (string, error)) (*tokenCatalogService, error) {
+	service, err := newTokenCatalogService(baseURL, "")
+	if err != nil {
+		return nil, err
+	}
+	service.token = token
+	return service, nil
+}
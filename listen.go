@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// unixAddressPrefix marks an --sse-address that is a Unix socket path, e.g.
+// unix:/run/enbuild-mcp.sock.
+const unixAddressPrefix = "unix:"
+
+// unixSocketMode limits a Unix socket to its owner, so only processes of
+// the same user can reach the server.
+const unixSocketMode fs.FileMode = 0o600
+
+// listen opens the listener for the SSE and HTTP servers: a Unix socket for
+// a "unix:" address and TCP otherwise. The socket file is removed when the
+// listener is closed, which http.Server.Shutdown does.
+func listen(addr string) (net.Listener, error) {
+	path, ok := strings.CutPrefix(addr, unixAddressPrefix)
+	if !ok {
+		return net.Listen("tcp", addr)
+	}
+	if path == "" {
+		return nil, fmt.Errorf("invalid address %q: missing socket path after %s", addr, unixAddressPrefix)
+	}
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, unixSocketMode); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("could not restrict permissions of socket %s: %v", path, err)
+	}
+	return ln, nil
+}
+
+// endpointAddress describes where an endpoint is served for the startup log:
+// host:port/path, or the socket and path for a Unix socket.
+func endpointAddress(addr, path string) string {
+	if strings.HasPrefix(addr, unixAddressPrefix) {
+		return addr + " at " + path
+	}
+	return addr + path
+}
+
+// removeStaleSocket deletes a socket left behind by a server that did not
+// shut down cleanly. A socket something still answers on, or a path that is
+// not a socket, is left alone and reported.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&fs.ModeSocket == 0 {
+		return fmt.Errorf("cannot listen on %s: the path exists and is not a socket", path)
+	}
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return fmt.Errorf("cannot listen on %s: another server is already listening there", path)
+	}
+	debugf("Removing stale socket %s", path)
+	return os.Remove(path)
+}
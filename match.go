@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+const defaultMatch = "contains"
+
+func getMatchParam(request mcp.CallToolRequest) (string, error) {
+	match, _ := request.GetArguments()["match"].(string)
+	match = strings.ToLower(strings.TrimSpace(match))
+	switch match {
+	case "":
+		return defaultMatch, nil
+	case "contains", "exact":
+		return match, nil
+	}
+	return "", fmt.Errorf("match must be either contains or exact")
+}
+
+// filterByName applies the name filter client-side, ignoring case, so the
+// result does not depend on how the API or SDK matches names.
+func filterByName(catalogs []*enbuild.Catalog, name, match string) []*enbuild.Catalog {
+	if name == "" {
+		return catalogs
+	}
+	needle := strings.ToLower(name)
+	filtered := make([]*enbuild.Catalog, 0, len(catalogs))
+	for _, c := range catalogs {
+		candidate := strings.ToLower(c.Name)
+		if (match == "exact" && candidate == needle) || (match != "exact" && strings.Contains(candidate, needle)) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
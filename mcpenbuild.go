@@ -1,13 +1,29 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"math"
+	"net/http"
+	"net/url"
 	"os"
+	"os/signal"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -21,91 +37,930 @@ const (
 )
 
 type enbuildConfig struct {
-	username string
-	password string
-	debug    bool
-	baseURL  string
+	username     string
+	password     string
+	passwordFile string
+	token        string
+	clientID     string
+	clientSecret string
+	debug        bool
+	baseURL      string
+	env          string
+	secretRef    string
+	config       string
+	profile      string
 }
 
 func (ec *enbuildConfig) addFlags() {
 	flag.StringVar(&ec.username, "username", "", "username for ENBUILD")
-	flag.StringVar(&ec.password, "password", "", "password for ENBUILD")
+	flag.StringVar(&ec.password, "password", "", "password for ENBUILD (\"-\" reads it from stdin)")
+	flag.StringVar(&ec.passwordFile, "password-file", "", "File containing the password for ENBUILD")
+	flag.StringVar(&ec.token, "token", "", "API bearer token for ENBUILD, used instead of username/password")
+	flag.StringVar(&ec.clientID, "client-id", "", "Keycloak client ID for the client-credentials grant, used instead of username/password")
+	flag.StringVar(&ec.clientSecret, "client-secret", "", "Keycloak client secret for the client-credentials grant")
 	flag.BoolVar(&ec.debug, "debug", false, "Enable debug mode for the ENBUILD client")
-	flag.StringVar(&ec.baseURL, "base-url", "https://enbuild.vivplatform.io", "Base URL for the ENBUILD")
+	flag.StringVar(&ec.baseURL, "base-url", defaultBaseURL, "Base URL for the ENBUILD")
+	flag.StringVar(&ec.env, "env", "", "Named ENBUILD environment whose base URL to use, e.g. prod or one from the --config file (or ENBUILD_ENV); used instead of --base-url")
+	flag.StringVar(&ec.secretRef, "secret-ref", "", "Secret manager reference holding username/password JSON (aws-sm://, gcp-sm://, or vault://)")
+	flag.StringVar(&ec.config, "config", "", "YAML or JSON file with username, password, token, base_url, timeout, and default_vcs (flags and env vars take precedence)")
+	flag.StringVar(&ec.profile, "profile", "", "Named profile to use from the --config file (default: the file's default_profile, or \"default\")")
 }
 
+// resolvePassword replaces --password - with a line read from stdin and
+// loads --password-file, trimming the trailing newline in both cases.
+func (ec *enbuildConfig) resolvePassword(stdin io.Reader) error {
+	if ec.passwordFile != "" {
+		if ec.password != "" {
+			return fmt.Errorf("use only one of --password and --password-file")
+		}
+		data, err := os.ReadFile(ec.passwordFile)
+		if err != nil {
+			return fmt.Errorf("failed to read password file: %v", err)
+		}
+		ec.password = strings.TrimRight(string(data), "\r\n")
+		if ec.password == "" {
+			return fmt.Errorf("password file %s is empty", ec.passwordFile)
+		}
+		return nil
+	}
+	if ec.password != "-" {
+		return nil
+	}
+
+	// Read one byte at a time so nothing past the first line is consumed;
+	// the stdio transport reads MCP messages from the same stream.
+	var line []byte
+	buf := make([]byte, 1)
+	for {
+		n, err := stdin.Read(buf)
+		if n == 1 {
+			if buf[0] == '\n' {
+				break
+			}
+			line = append(line, buf[0])
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read password from stdin: %v", err)
+		}
+	}
+	ec.password = strings.TrimRight(string(line), "\r")
+	if ec.password == "" {
+		return fmt.Errorf("no password was read from stdin")
+	}
+	return nil
+}
+
+// warnPasswordFlag warns when the password was given literally on the
+// command line, where other users can see it in process listings and it
+// lands in shell history. It must run before resolvePassword, which
+// replaces "-" and --password-file with the password itself.
+func (ec enbuildConfig) warnPasswordFlag() {
+	if ec.password == "" || ec.password == "-" {
+		return
+	}
+	warnf("The password was passed with --password, which exposes it in process listings and shell history; use --password-file, --password - (stdin), or ENBUILD_PASSWORD instead")
+}
+
+// credentialSource describes where the startup credentials came from, for
+// debug logging. It never includes the password, token, or client secret.
+func (ec enbuildConfig) credentialSource() string {
+	env := "environment variables"
+	if ec.config != "" {
+		env = "environment variables or --config file"
+	}
+	switch {
+	case !hasStartupCredentials():
+		return "none (each tool call passes its own)"
+	case ec.token != "":
+		return "--token flag"
+	case os.Getenv("ENBUILD_TOKEN") != "" && ec.config != "":
+		return "ENBUILD_TOKEN environment variable or --config file"
+	case os.Getenv("ENBUILD_TOKEN") != "":
+		return "ENBUILD_TOKEN environment variable"
+	case ec.clientID != "":
+		return fmt.Sprintf("--client-id flag (client %s)", ec.clientID)
+	case os.Getenv("ENBUILD_CLIENT_ID") != "":
+		return fmt.Sprintf("ENBUILD_CLIENT_ID environment variable (client %s)", os.Getenv("ENBUILD_CLIENT_ID"))
+	case ec.secretRef != "":
+		return fmt.Sprintf("--secret-ref %s (username %s)", ec.secretRef, os.Getenv("ENBUILD_USERNAME"))
+	case ec.username != "" && ec.password != "":
+		return fmt.Sprintf("--username/--password flags (username %s)", ec.username)
+	case ec.username != "" || ec.password != "":
+		return fmt.Sprintf("flags and ENBUILD_USERNAME/ENBUILD_PASSWORD %s (username %s)", env, os.Getenv("ENBUILD_USERNAME"))
+	default:
+		return fmt.Sprintf("ENBUILD_USERNAME/ENBUILD_PASSWORD %s (username %s)", env, os.Getenv("ENBUILD_USERNAME"))
+	}
+}
+
+// responseAPIVersion identifies the shape of CatalogResponse. Bump it when
+// the envelope changes incompatibly so clients can detect the change.
+const responseAPIVersion = "v1"
+
 type CatalogResponse struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message,omitempty"`
-	Count   int         `json:"count,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
+	APIVersion  string      `json:"api_version" yaml:"api_version"`
+	Success     bool        `json:"success" yaml:"success"`
+	Message     string      `json:"message,omitempty" yaml:"message,omitempty"`
+	ErrorType   string      `json:"error_type,omitempty" yaml:"error_type,omitempty"`
+	ErrorCode   string      `json:"error_code,omitempty" yaml:"error_code,omitempty"`
+	StatusCode  int         `json:"status_code,omitempty" yaml:"status_code,omitempty"`
+	RetryAfter  int         `json:"retry_after_seconds,omitempty" yaml:"retry_after_seconds,omitempty"`
+	Count       int         `json:"count,omitempty" yaml:"count,omitempty"`
+	Total       int         `json:"total,omitempty" yaml:"total,omitempty"`
+	Found       *bool       `json:"found,omitempty" yaml:"found,omitempty"` // searches only; false when nothing matched
+	Data        interface{} `json:"data,omitempty" yaml:"data,omitempty"`
+	ResumeToken string      `json:"resume_token,omitempty" yaml:"resume_token,omitempty"`
+	Truncated   bool        `json:"truncated,omitempty" yaml:"truncated,omitempty"`
+	RequestID   string      `json:"request_id,omitempty" yaml:"request_id,omitempty"`
+}
+
+const errorCodeInvalidToken = "INVALID_TOKEN"
+
+func newServer() (*server.MCPServer, error) {
+	s := server.NewMCPServer(serverName, serverVersion,
+		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(false, false),
+		server.WithPromptCapabilities(false),
+		server.WithRecovery(),
+		server.WithToolHandlerMiddleware(outputFormatMiddleware),
+		server.WithToolHandlerMiddleware(requestIDMiddleware),
+		server.WithToolHandlerMiddleware(auditMiddleware),
+		server.WithToolHandlerMiddleware(tracingMiddleware),
+		server.WithToolHandlerMiddleware(responseSizeMiddleware),
+		server.WithToolHandlerMiddleware(truncateFieldsMiddleware),
+		server.WithToolHandlerMiddleware(projectFieldsMiddleware),
+	)
+	if err := registerTools(s); err != nil {
+		return nil, fmt.Errorf("failed to register tools: %w", err)
+	}
+	registerResources(s)
+	registerPrompts(s)
+	return s, nil
+}
+
+func registerTools(s *server.MCPServer) error {
+	tools := []server.ServerTool{
+		{Tool: mcp.NewTool("get_catalog_details",
+			mcp.WithDescription("Fetches details of all catalogs that match a specific catalog ID."),
+			readOnlyTool(),
+			mcp.WithString("id", mcp.Description("ID of the catalog"), mcp.Required()),
+			mcp.WithString("username", mcp.Description("API username to use")),
+			mcp.WithString("password", mcp.Description("API password to use")),
+			mcp.WithString("token", mcp.Description("API bearer token to use instead of username and password")),
+		), Handler: getCatalogDetails},
+		{Tool: mcp.NewTool("ping",
+			mcp.WithDescription("Checks connectivity and credentials by making a lightweight authenticated call to ENBUILD. Returns the base URL on success, or whether the failure was authentication or connectivity."),
+			readOnlyTool(),
+			mcp.WithString("username", mcp.Description("API username to use")),
+			mcp.WithString("password", mcp.Description("API password to use")),
+			mcp.WithString("token", mcp.Description("API bearer token to use instead of username and password")),
+		), Handler: ping},
+		{Tool: mcp.NewTool("reset_client",
+			mcp.WithDescription("Discards cached authenticated ENBUILD clients so the next call logs in again, e.g. after a password or token is rotated. With credential arguments, only the client for those credentials is discarded."),
+			readOnlyTool(),
+			mcp.WithString("username", mcp.Description("API username whose client to discard")),
+			mcp.WithString("password", mcp.Description("API password whose client to discard")),
+			mcp.WithString("token", mcp.Description("API bearer token whose client to discard")),
+		), Handler: resetClient},
+		{Tool: mcp.NewTool("list_vcs",
+			mcp.WithDescription("Lists the VCS providers catalogs can come from, the valid values of the vcs argument. Does not contact ENBUILD."),
+			readOnlyTool(),
+		), Handler: listVCS},
+		{Tool: mcp.NewTool("server_info",
+			mcp.WithDescription("Returns the name, version, and description of this MCP server. Does not contact ENBUILD."),
+			readOnlyTool(),
+		), Handler: getServerInfo},
+		{Tool: mcp.NewTool("search_catalogs",
+			mcp.WithDescription("Search for catalogs in a VCS, optionally filtered by name and catalog type. Omit name to list every catalog of a type."),
+			readOnlyTool(),
+			mcp.WithString("name", mcp.Description("Name to search for; omit to match any name")),
+			mcp.WithString("match", mcp.Description("How name is matched, ignoring case: contains (default) or exact"), mcp.Enum("contains", "exact")),
+			mcp.WithString("type", mcp.Description("Type to filter by (e.g., terraform, ansible); a comma-separated list such as terraform,helm matches any of them; omit to match any type")),
+			vcsArgument(),
+			mcp.WithNumber("limit", mcp.Description("Maximum number of catalogs to return; a resume_token is returned when more remain")),
+			mcp.WithNumber("offset", mcp.Description("Number of matching catalogs to skip (ignored when resume_token is set)")),
+			mcp.WithString("resume_token", mcp.Description("Token from a previous search_catalogs response to continue where it left off")),
+			mcp.WithString("sort", mcp.Description("Field to sort results by (default name)"), mcp.Enum("name", "type", "created")),
+			mcp.WithString("order", mcp.Description("Sort order (default asc)"), mcp.Enum("asc", "desc")),
+			mcp.WithString("username", mcp.Description("API username to use")),
+			mcp.WithString("password", mcp.Description("API password to use")),
+			mcp.WithString("token", mcp.Description("API bearer token to use instead of username and password")),
+		), Handler: searchCatalogs},
+		{Tool: mcp.NewTool("get_catalogs",
+			mcp.WithDescription("Fetches several catalogs by ID in one call. Each entry has the catalog, or an error if that ID could not be fetched."),
+			readOnlyTool(),
+			mcp.WithArray("ids", mcp.Description(fmt.Sprintf("Catalog IDs to fetch, at most %d; a comma-separated string is also accepted", maxBatchIDs)), mcp.Items(map[string]interface{}{"type": "string"}), mcp.Required()),
+			mcp.WithString("username", mcp.Description("API username to use")),
+			mcp.WithString("password", mcp.Description("API password to use")),
+			mcp.WithString("token", mcp.Description("API bearer token to use instead of username and password")),
+		), Handler: getCatalogs},
+		{Tool: mcp.NewTool("count_catalogs",
+			mcp.WithDescription("Counts the catalogs search_catalogs would return for the same filters, without returning them."),
+			readOnlyTool(),
+			mcp.WithString("name", mcp.Description("Name to search for; omit to match any name")),
+			mcp.WithString("match", mcp.Description("How name is matched, ignoring case: contains (default) or exact"), mcp.Enum("contains", "exact")),
+			mcp.WithString("type", mcp.Description("Type to filter by (e.g., terraform, ansible); a comma-separated list such as terraform,helm matches any of them; omit to match any type")),
+			vcsArgument(),
+			mcp.WithString("username", mcp.Description("API username to use")),
+			mcp.WithString("password", mcp.Description("API password to use")),
+			mcp.WithString("token", mcp.Description("API bearer token to use instead of username and password")),
+		), Handler: countCatalogs},
+		{Tool: mcp.NewTool("search_catalogs_regex",
+			mcp.WithDescription("Lists catalogs in a VCS whose names match a regular expression (RE2 syntax), e.g. -deprecated$. Matching is case-sensitive unless the pattern starts with (?i)."),
+			readOnlyTool(),
+			mcp.WithString("pattern", mcp.Description(fmt.Sprintf("Regular expression to match catalog names against, at most %d bytes", maxPatternLength)), mcp.Required()),
+			mcp.WithString("type", mcp.Description("Type to filter by (e.g., terraform, ansible); a comma-separated list such as terraform,helm matches any of them")),
+			vcsArgument(),
+			mcp.WithString("username", mcp.Description("API username to use")),
+			mcp.WithString("password", mcp.Description("API password to use")),
+			mcp.WithString("token", mcp.Description("API bearer token to use instead of username and password")),
+		), Handler: searchCatalogsRegex},
+		{Tool: mcp.NewTool("get_catalog_readme",
+			mcp.WithDescription("Returns a catalog's README or description as plain text. Data is an empty string when the catalog has no documentation."),
+			readOnlyTool(),
+			mcp.WithString("id", mcp.Description("ID of the catalog"), mcp.Required()),
+			mcp.WithString("username", mcp.Description("API username to use")),
+			mcp.WithString("password", mcp.Description("API password to use")),
+			mcp.WithString("token", mcp.Description("API bearer token to use instead of username and password")),
+		), Handler: getCatalogReadme},
+		{Tool: mcp.NewTool("check_catalog_repo",
+			mcp.WithDescription("Checks whether a catalog's backing repository is reachable with an unauthenticated HEAD request, returning reachable and the HTTP status. Private repositories usually report 404."),
+			readOnlyTool(),
+			mcp.WithString("id", mcp.Description("ID of the catalog"), mcp.Required()),
+			mcp.WithString("username", mcp.Description("API username to use")),
+			mcp.WithString("password", mcp.Description("API password to use")),
+			mcp.WithString("token", mcp.Description("API bearer token to use instead of username and password")),
+		), Handler: checkCatalogRepo},
+		{Tool: mcp.NewTool("list_catalogs_by_vcs",
+			mcp.WithDescription("Lists catalogs from every supported VCS ("+strings.Join(SupportedVCS, ", ")+") in one call, merged by catalog ID and sorted by name. Each catalog's source_vcs lists the VCS it was found in."),
+			readOnlyTool(),
+			mcp.WithString("vcs", mcp.Description(allVCS+" (default) for every VCS, or "+vcsChoices()+" for one"), mcp.Enum(append([]string{allVCS}, SupportedVCS...)...)),
+			mcp.WithString("type", mcp.Description("Type to filter by (e.g., terraform, ansible); a comma-separated list such as terraform,helm matches any of them")),
+			mcp.WithString("username", mcp.Description("API username to use")),
+			mcp.WithString("password", mcp.Description("API password to use")),
+			mcp.WithString("token", mcp.Description("API bearer token to use instead of username and password")),
+		), Handler: listCatalogsByVCS},
+		{Tool: mcp.NewTool("list_catalog_types",
+			mcp.WithDescription("Lists the distinct catalog types (e.g., terraform, ansible) that can be used as the type filter in search_catalogs."),
+			readOnlyTool(),
+			mcp.WithString("vcs", mcp.Description("Only include catalogs from this VCS ("+vcsChoices()+")")),
+			mcp.WithString("username", mcp.Description("API username to use")),
+			mcp.WithString("password", mcp.Description("API password to use")),
+			mcp.WithString("token", mcp.Description("API bearer token to use instead of username and password")),
+		), Handler: listCatalogTypes},
+		{Tool: mcp.NewTool("get_catalog_inputs",
+			mcp.WithDescription("Lists the input variables a catalog declares, with each one's name, type, default, description, and whether it is required, so a user can be helped to fill them in."),
+			readOnlyTool(),
+			mcp.WithString("id", mcp.Description("ID of the catalog"), mcp.Required()),
+			mcp.WithString("version", mcp.Description("Catalog version expected; only the current version can be read, so another version fails with not_found")),
+			mcp.WithString("username", mcp.Description("API username to use")),
+			mcp.WithString("password", mcp.Description("API password to use")),
+			mcp.WithString("token", mcp.Description("API bearer token to use instead of username and password")),
+		), Handler: getCatalogInputs},
+		{Tool: mcp.NewTool("get_catalog_deployments",
+			mcp.WithDescription("Lists a catalog's recent deployment records (status, timestamp, user), newest first. The records come from the catalog's own content because ENBUILD exposes no deployments API to this server, so the list may be empty or incomplete."),
+			readOnlyTool(),
+			mcp.WithString("id", mcp.Description("ID of the catalog"), mcp.Required()),
+			mcp.WithNumber("limit", mcp.Description(fmt.Sprintf("Maximum number of records to return (default %d)", defaultDeploymentLimit))),
+			mcp.WithString("username", mcp.Description("API username to use")),
+			mcp.WithString("password", mcp.Description("API password to use")),
+			mcp.WithString("token", mcp.Description("API bearer token to use instead of username and password")),
+		), Handler: getCatalogDeployments},
+		{Tool: mcp.NewTool("list_writable_catalogs",
+			mcp.WithDescription("Lists the catalogs the authenticated identity may modify. ENBUILD exposes no permission data to this server, so every accessible catalog is returned with a warning that write access was not checked."),
+			readOnlyTool(),
+			mcp.WithString("vcs", mcp.Description(allVCS+" (default) for every VCS, or "+vcsChoices()+" for one")),
+			mcp.WithString("username", mcp.Description("API username to use")),
+			mcp.WithString("password", mcp.Description("API password to use")),
+			mcp.WithString("token", mcp.Description("API bearer token to use instead of username and password")),
+		), Handler: listWritableCatalogs},
+		{Tool: mcp.NewTool("compare_catalogs",
+			mcp.WithDescription("Compares two catalogs field by field and reports which fields are the same, which differ (with both values), and which only one of them has. Nested fields such as content are compared by path, e.g. content.inputs.region."),
+			readOnlyTool(),
+			mcp.WithString("id_a", mcp.Description("ID of the first catalog"), mcp.Required()),
+			mcp.WithString("id_b", mcp.Description("ID of the second catalog"), mcp.Required()),
+			mcp.WithString("username", mcp.Description("API username to use")),
+			mcp.WithString("password", mcp.Description("API password to use")),
+			mcp.WithString("token", mcp.Description("API bearer token to use instead of username and password")),
+		), Handler: compareCatalogs},
+		{Tool: mcp.NewTool("catalog_stats",
+			mcp.WithDescription("Summarizes the catalog inventory: the total number of catalogs and counts grouped by type and by VCS, without returning the catalogs themselves."),
+			readOnlyTool(),
+			mcp.WithString("vcs", mcp.Description(allVCS+" (default) for every VCS, or "+vcsChoices()+" for one")),
+			mcp.WithString("username", mcp.Description("API username to use")),
+			mcp.WithString("password", mcp.Description("API password to use")),
+			mcp.WithString("token", mcp.Description("API bearer token to use instead of username and password")),
+		), Handler: catalogStatsTool},
+		{Tool: mcp.NewTool("validate_catalog_inputs",
+			mcp.WithDescription("Checks input values against a catalog's declared inputs before deploying. Reports type mismatches, missing required inputs, and unknown keys."),
+			readOnlyTool(),
+			mcp.WithString("id", mcp.Description("ID of the catalog"), mcp.Required()),
+			mcp.WithString("version", mcp.Description("Catalog version expected; only the current version can be read, so another version fails with not_found")),
+			mcp.WithObject("inputs", mcp.Description("Input values keyed by input name"), mcp.Required()),
+			mcp.WithString("username", mcp.Description("API username to use")),
+			mcp.WithString("password", mcp.Description("API password to use")),
+			mcp.WithString("token", mcp.Description("API bearer token to use instead of username and password")),
+		), Handler: validateCatalogInputs},
+		{Tool: mcp.NewTool("catalog_freshness",
+			mcp.WithDescription("Lists catalogs annotated with a freshness score from 0 to 1 based on how recently each was updated, freshest first. Catalogs without a timestamp get a null score."),
+			readOnlyTool(),
+			vcsArgument(),
+			mcp.WithString("name", mcp.Description("Name to search for")),
+			mcp.WithString("type", mcp.Description("Type to filter by (e.g., terraform, ansible); a comma-separated list such as terraform,helm matches any of them")),
+			mcp.WithString("username", mcp.Description("API username to use")),
+			mcp.WithString("password", mcp.Description("API password to use")),
+			mcp.WithString("token", mcp.Description("API bearer token to use instead of username and password")),
+		), Handler: catalogFreshness},
+		{Tool: mcp.NewTool("estimate_search_cost",
+			mcp.WithDescription("Estimates how large a search_catalogs response would be (catalog count, bytes, and approximate tokens) without returning the catalogs. Use it before a broad search to decide whether to page."),
+			readOnlyTool(),
+			vcsArgument(),
+			mcp.WithString("name", mcp.Description("Name to search for")),
+			mcp.WithString("match", mcp.Description("How name is matched, ignoring case: contains (default) or exact"), mcp.Enum("contains", "exact")),
+			mcp.WithString("type", mcp.Description("Type to filter by (e.g., terraform, ansible); a comma-separated list such as terraform,helm matches any of them")),
+			mcp.WithString("username", mcp.Description("API username to use")),
+			mcp.WithString("password", mcp.Description("API password to use")),
+			mcp.WithString("token", mcp.Description("API bearer token to use instead of username and password")),
+		), Handler: estimateSearchCost},
+	}
+	if currentLogLevel == levelDebug {
+		tools = append(tools, server.ServerTool{Tool: mcp.NewTool("debug_config",
+			mcp.WithDescription("Returns the server's effective configuration (base URL, transport, auth method, and other settings) and where each value came from, with secrets redacted. Only available with --log-level debug."),
+			readOnlyTool(),
+		), Handler: debugConfig})
+	}
+
+	for i := range tools {
+		tools[i].Tool.InputSchema.Properties["max_field_length"] = map[string]interface{}{
+			"type":        "number",
+			"description": "Truncate string fields in the result longer than this many characters (overrides --max-field-length; 0 disables)",
+		}
+		tools[i].Tool.InputSchema.Properties["format"] = map[string]interface{}{
+			"type":        "string",
+			"description": "Output format of the result: json (default) or yaml",
+			"enum":        []string{"json", "yaml"},
+		}
+		// Every tool that takes a token also accepts Keycloak client
+		// credentials in its place.
+		if _, ok := tools[i].Tool.InputSchema.Properties["token"]; ok {
+			tools[i].Tool.InputSchema.Properties["client_id"] = map[string]interface{}{
+				"type":        "string",
+				"description": "Keycloak client ID to authenticate with instead of username and password (requires client_secret)",
+			}
+			tools[i].Tool.InputSchema.Properties["client_secret"] = map[string]interface{}{
+				"type":        "string",
+				"description": "Keycloak client secret for client_id",
+			}
+		}
+		if _, ok := tools[i].Tool.InputSchema.Properties["token"]; ok && len(backends) > 0 {
+			tools[i].Tool.InputSchema.Properties["backend"] = map[string]interface{}{
+				"type":        "string",
+				"description": "Configured ENBUILD backend to call instead of the server's own; its base URL and credentials are used unless credentials are passed",
+				"enum":        backendNames(),
+			}
+		}
+		if fieldsTools[tools[i].Tool.Name] {
+			tools[i].Tool.InputSchema.Properties["fields"] = map[string]interface{}{
+				"type":        "string",
+				"description": "Comma-separated catalog fields to return (e.g. id,name,type); unknown names are ignored",
+			}
+		}
+	}
+
+	for i := range tools {
+		tools[i].Handler = instrumentTool(tools[i].Tool.Name, tools[i].Handler)
+	}
+
+	tools = filterDisabled(tools)
+	tools = filterReadOnly(tools)
+	if err := applyToolDescriptions(tools, os.Getenv("ENBUILD_TOOL_DESCRIPTIONS")); err != nil {
+		return err
+	}
+	if err := validateTools(tools); err != nil {
+		return err
+	}
+	s.AddTools(tools...)
+	return nil
 }
 
-func newServer() *server.MCPServer {
-	s := server.NewMCPServer(serverName, serverVersion, server.WithToolCapabilities(true), server.WithRecovery())
-	registerTools(s)
-	return s
+// validateTools reports every problem that would make a tool unusable.
+// mcp-go's AddTools accepts anything and silently replaces duplicates, so
+// mistakes are caught here at startup instead.
+func validateTools(tools []server.ServerTool) error {
+	var errs []error
+	seen := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		name := tool.Tool.Name
+		switch {
+		case name == "":
+			errs = append(errs, fmt.Errorf("a tool has no name"))
+			continue
+		case seen[name]:
+			errs = append(errs, fmt.Errorf("tool %s is registered more than once", name))
+		}
+		seen[name] = true
+		if tool.Handler == nil {
+			errs = append(errs, fmt.Errorf("tool %s has no handler", name))
+		}
+		for _, arg := range tool.Tool.InputSchema.Required {
+			if _, ok := tool.Tool.InputSchema.Properties[arg]; !ok {
+				errs = append(errs, fmt.Errorf("tool %s requires undeclared argument %s", name, arg))
+			}
+		}
+	}
+	return errors.Join(errs...)
 }
 
-func registerTools(s *server.MCPServer) {
-	s.AddTool(mcp.NewTool("get_catalog_details",
-		mcp.WithDescription("Fetches details of all catalogs that match a specific catalog ID."),
-		mcp.WithString("id", mcp.Description("ID of the catalog"), mcp.Required()),
-		mcp.WithString("username", mcp.Description("API username to use")),
-		mcp.WithString("password", mcp.Description("API password to use")),
-	), getCatalogDetails)
+// applyToolDescriptions replaces tool descriptions with those from a JSON
+// file mapping tool name to description.
+func applyToolDescriptions(tools []server.ServerTool, path string) error {
+	if path == "" {
+		return nil
+	}
 
-	s.AddTool(mcp.NewTool("search_catalogs",
-		mcp.WithDescription("Search for catalogs using name, filtered by catalog type and VCS."),
-		mcp.WithString("name", mcp.Description("Name to search for"), mcp.Required()),
-		mcp.WithString("type", mcp.Description("Type to filter by (e.g., terraform, ansible)"), mcp.Required()),
-		mcp.WithString("vcs", mcp.Description("VCS to filter by (GITHUB or GITLAB)"), mcp.Required()),
-		mcp.WithString("username", mcp.Description("API username to use")),
-		mcp.WithString("password", mcp.Description("API password to use")),
-	), searchCatalogs)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read tool descriptions file: %v", err)
+	}
+	var overrides map[string]string
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return fmt.Errorf("tool descriptions file %s must be a JSON object of tool name to description: %v", path, err)
+	}
+
+	known := make(map[string]bool, len(tools))
+	for i := range tools {
+		known[tools[i].Tool.Name] = true
+		if description, ok := overrides[tools[i].Tool.Name]; ok {
+			tools[i].Tool.Description = description
+		}
+	}
+	for name := range overrides {
+		if !known[name] {
+			warnf("Tool descriptions file %s references unknown tool: %s", path, name)
+		}
+	}
+	return nil
 }
 
-func run(transport, addr, logLevel string, ec enbuildConfig) error {
-	log.SetFlags(0)
-	log.Printf("[INFO] Starting ENBUILD MCP server with transport: %s", transport)
+const (
+	defaultMaxRequestBytes = 4 << 20
+	streamableHTTPPath     = "/mcp"
+	metricsPath            = "/metrics"
+)
+
+func run(transport, addr, basePath, logLevel, authToken string, maxRequestBytes int64, metrics bool, ec enbuildConfig) error {
+	if err := setLogLevel(logLevel); err != nil {
+		return err
+	}
+	infof("Starting ENBUILD MCP server with transport: %s", transport)
+	debugf("Using ENBUILD base URL: %s", os.Getenv("ENBUILD_BASE_URL"))
+	debugf("Using ENBUILD credentials from %s", ec.credentialSource())
+	recordStartupConfig(transport, addr, basePath, ec)
+	if !hasStartupCredentials() {
+		warnf("No ENBUILD credentials configured; every tool call must pass its own token, client_id and client_secret, or username and password")
+	}
+	if err := configureConnectionPool(); err != nil {
+		return err
+	}
+	if err := configureProxy(os.Getenv("ENBUILD_BASE_URL")); err != nil {
+		return err
+	}
+	if err := configureTransport(os.Getenv("ENBUILD_BASE_URL")); err != nil {
+		return err
+	}
+	flushTraces, err := configureTracing()
+	if err != nil {
+		return err
+	}
+	defer flushTraces()
+	closeAuditLog, err := configureAuditLog()
+	if err != nil {
+		return err
+	}
+	defer closeAuditLog()
 
-	s := newServer()
+	s, err := newServer()
+	if err != nil {
+		return err
+	}
 
 	switch transport {
 	case "stdio":
 		srv := server.NewStdioServer(s)
-		log.Println("Starting ENBUILD MCP server using stdio transport")
+		if metrics {
+			warnf("--metrics is ignored with the stdio transport")
+		}
+		infof("Starting ENBUILD MCP server using stdio transport")
 		return srv.Listen(context.Background(), os.Stdin, os.Stdout)
 	case "sse":
-		srv := server.NewSSEServer(s)
-		log.Printf("Starting ENBUILD MCP server using SSE transport on address: %s", addr)
-		if err := srv.Start(addr); err != nil {
-			return fmt.Errorf("server error: %v", err)
+		httpSrv := &http.Server{}
+		// The base path is also written into the endpoint event, so clients
+		// post messages under the same prefix.
+		srv := server.NewSSEServer(s, server.WithHTTPServer(httpSrv), server.WithStaticBasePath(basePath), server.WithSSEContextFunc(requestContextFromHeaders))
+		mux := http.NewServeMux()
+		mux.Handle("/", requireBearerToken(limitRequestBody(srv, maxRequestBytes), authToken))
+		if metrics {
+			mux.Handle(basePath+metricsPath, requireBearerToken(http.HandlerFunc(metricsHandler), authToken))
+		}
+		httpSrv.Handler = mux
+		ln, err := listen(addr)
+		if err != nil {
+			return err
+		}
+		infof("Starting ENBUILD MCP server using SSE transport on address: %s", endpointAddress(addr, srv.CompleteSsePath()))
+		return serveUntilSignal(func() error { return httpSrv.Serve(ln) }, srv.Shutdown)
+	case "http":
+		// The handler is mounted on our own server, rather than via Start,
+		// so the request body limit applies as it does for SSE.
+		mux := http.NewServeMux()
+		mux.Handle(basePath+streamableHTTPPath, requireBearerToken(limitRequestBody(server.NewStreamableHTTPServer(s, server.WithHTTPContextFunc(requestContextFromHeaders)), maxRequestBytes), authToken))
+		if metrics {
+			mux.Handle(basePath+metricsPath, requireBearerToken(http.HandlerFunc(metricsHandler), authToken))
 		}
+		httpSrv := &http.Server{Addr: addr, Handler: mux}
+		ln, err := listen(addr)
+		if err != nil {
+			return err
+		}
+		infof("Starting ENBUILD MCP server using streamable HTTP transport on address: %s", endpointAddress(addr, basePath+streamableHTTPPath))
+		return serveUntilSignal(func() error { return httpSrv.Serve(ln) }, httpSrv.Shutdown)
 	default:
-		return fmt.Errorf("invalid transport type: %s. Must be 'stdio' or 'sse'", transport)
+		return fmt.Errorf("invalid transport type: %s. Must be 'stdio', 'sse', or 'http'", transport)
+	}
+}
+
+// normalizeBasePath turns a --base-path value such as "enbuild/" into
+// "/enbuild". An empty path or "/" mounts the server at the root and is
+// returned as "".
+func normalizeBasePath(basePath string) string {
+	basePath = strings.Trim(strings.TrimSpace(basePath), "/")
+	if basePath == "" {
+		return ""
 	}
+	return path.Clean("/" + basePath)
+}
+
+// shutdownTimeout bounds how long in-flight requests may take to drain
+// after SIGINT or SIGTERM.
+const shutdownTimeout = 10 * time.Second
+
+// serveUntilSignal runs start until it fails or the process receives
+// SIGINT/SIGTERM, in which case shutdown is given shutdownTimeout to drain
+// in-flight requests.
+func serveUntilSignal(start func() error, shutdown func(context.Context) error) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- start() }()
+
+	select {
+	case err := <-errCh:
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("server error: %v", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	infof("Shutting down ENBUILD MCP server, waiting up to %s for in-flight requests", shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("server shutdown error: %v", err)
+	}
+	infof("ENBUILD MCP server stopped")
 	return nil
 }
 
+// limitRequestBody rejects request bodies larger than maxBytes with a 413.
+// A non-positive maxBytes disables the limit.
+func limitRequestBody(next http.Handler, maxBytes int64) http.Handler {
+	if maxBytes <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body == nil || r.Method == http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.ContentLength > maxBytes {
+			writeRequestTooLarge(w, maxBytes)
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBytes))
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				writeRequestTooLarge(w, maxBytes)
+				return
+			}
+			http.Error(w, fmt.Sprintf("failed to read request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireBearerToken rejects requests whose Authorization header does not
+// carry the given bearer token with a 401. An empty token disables the check.
+func requireBearerToken(next http.Handler, token string) http.Handler {
+	if token == "" {
+		return next
+	}
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			writeUnauthorized(w)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeUnauthorized(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("WWW-Authenticate", `Bearer realm="mcp-server-enbuild"`)
+	w.WriteHeader(http.StatusUnauthorized)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      nil,
+		"error": map[string]interface{}{
+			"code":    mcp.INVALID_REQUEST,
+			"message": "missing or invalid bearer token",
+		},
+	})
+}
+
+func writeRequestTooLarge(w http.ResponseWriter, maxBytes int64) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      nil,
+		"error": map[string]interface{}{
+			"code":    mcp.INVALID_REQUEST,
+			"message": fmt.Sprintf("request body exceeds the %d byte limit", maxBytes),
+		},
+	})
+}
+
 func main() {
+	recordStartupEnvironment()
 	var transport string
-	flag.StringVar(&transport, "transport", "stdio", "Transport type (stdio or sse)")
-	addr := flag.String("sse-address", ":8080", "The host and port to start the SSE server on")
+	flag.StringVar(&transport, "transport", "stdio", "Transport type (stdio, sse, or http)")
+	addr := flag.String("sse-address", ":8080", "The host and port to start the SSE or streamable HTTP server on, or unix:/path for a Unix socket")
+	basePath := flag.String("base-path", "", "Path prefix for the SSE and HTTP endpoints, e.g. /enbuild when behind a reverse proxy")
 	logLevel := flag.String("log-level", "info", "Log level (debug, info, warn, error)")
+	logFormat := flag.String("log-format", "text", "Log format (text or json)")
+	version := flag.Bool("version", false, "Print the server version and exit")
+	selfTest := flag.Bool("self-test", false, "Run every tool against an in-memory catalog service and exit")
+	toolDescriptions := flag.String("tool-descriptions", "", "JSON file mapping tool names to description overrides")
+	deterministicOrder := flag.Bool("deterministic-order", false, "Sort list results by catalog ID, and break ties in sorted search results by ID, for reproducible output")
+	maxFieldLength := flag.Int("max-field-length", 0, "Truncate string fields in tool results longer than this many characters (0 disables)")
+	maxResponseBytes := flag.Int("max-response-bytes", 0, "Drop items from the end of tool result lists so each JSON result stays under this many bytes (0 disables)")
+	freshnessWindow := flag.Duration("freshness-window", 0, "Age at which catalog_freshness scores reach 0 (default 4320h)")
+	maxRequestBytes := flag.Int64("max-request-bytes", defaultMaxRequestBytes, "Maximum request body size in bytes accepted by the SSE and HTTP servers (0 disables the limit)")
+	catalogURLTemplate := flag.String("catalog-url-template", "", "Template for catalog web links, with {base_url}, {id}, and {slug} placeholders (default \""+defaultCatalogURLTemplate+"\")")
+	rateLimit := flag.Float64("rate-limit", 0, "Maximum ENBUILD API requests per second (0 means unlimited)")
+	rateBurst := flag.Int("rate-burst", 0, "Requests allowed in a burst above --rate-limit (default: the rate, at least 1)")
+	timeout := flag.Duration("timeout", 0, "Timeout for each ENBUILD API request, e.g. 30s (or ENBUILD_TIMEOUT; 0 keeps the client default)")
+	maxRetryWait := flag.Duration("max-retry-wait", 0, "Longest Retry-After to wait for before retrying a rate-limited request once (or ENBUILD_MAX_RETRY_WAIT; default 10s, 0s disables retries)")
+	maxIdleConns := flag.Int("max-idle-conns", 0, "Maximum idle connections kept open across all hosts (or ENBUILD_MAX_IDLE_CONNS; default 100)")
+	maxIdleConnsPerHost := flag.Int("max-idle-conns-per-host", 0, "Maximum idle connections kept open to each host (or ENBUILD_MAX_IDLE_CONNS_PER_HOST; default 2)")
+	idleConnTimeout := flag.Duration("idle-conn-timeout", 0, "How long an idle connection is kept open (or ENBUILD_IDLE_CONN_TIMEOUT; default 90s)")
+	cacheTTL := flag.Duration("cache-ttl", 0, "Cache catalog list and get results in memory for this long (0 disables caching)")
+	metrics := flag.Bool("metrics", false, "Expose Prometheus metrics at /metrics on the SSE and HTTP servers")
+	proxy := flag.String("proxy", "", "HTTP, HTTPS, or SOCKS5 proxy URL for ENBUILD requests (or ENBUILD_PROXY; HTTP_PROXY and HTTPS_PROXY are honored otherwise)")
+	readOnly := flag.Bool("read-only", false, "Register only tools that do not modify ENBUILD (or ENBUILD_READ_ONLY=true)")
+	tokenCache := flag.Bool("token-cache", false, "Cache Keycloak tokens for the startup credentials on disk across restarts (or ENBUILD_TOKEN_CACHE=true)")
+	insecure := flag.Bool("insecure", false, "Skip TLS certificate verification for ENBUILD requests (or ENBUILD_INSECURE_SKIP_VERIFY=true)")
+	var disabledTools stringListFlag
+	flag.Var(&disabledTools, "disable-tool", "Tool name not to register; may be repeated or comma-separated (adds to ENBUILD_DISABLED_TOOLS)")
+	var headers stringListFlag
+	flag.Var(&headers, "header", "Custom header key=value to send on every ENBUILD request; may be repeated (adds to ENBUILD_HEADERS)")
+	auditLogPath := flag.String("audit-log", "", "File to append a JSON audit entry to for every tool call, with credentials redacted (or ENBUILD_AUDIT_LOG)")
+	authToken := flag.String("auth-token", "", "Bearer token clients must present to the SSE and HTTP servers (or ENBUILD_AUTH_TOKEN)")
 
 	var ec enbuildConfig
 	ec.addFlags()
 
 	flag.Parse()
 
-	// Retrieve credentials and baseURL, set them as environment variables
-	setEnvOrExit("ENBUILD_USERNAME", ec.username, "--username flag")
-	setEnvOrExit("ENBUILD_PASSWORD", ec.password, "--password flag")
+	if *version {
+		fmt.Printf("enbuild-mcp-server %s\n", serverVersion)
+		return
+	}
+
+	log.SetFlags(0)
+	if err := setLogFormat(*logFormat); err != nil {
+		fatalf("Error: %v", err)
+	}
+
+	if *toolDescriptions != "" {
+		os.Setenv("ENBUILD_TOOL_DESCRIPTIONS", *toolDescriptions)
+	}
+	if *deterministicOrder {
+		os.Setenv("ENBUILD_DETERMINISTIC_ORDER", "true")
+	}
+	if *maxFieldLength > 0 {
+		os.Setenv("ENBUILD_MAX_FIELD_LENGTH", strconv.Itoa(*maxFieldLength))
+	}
+	if *maxResponseBytes > 0 {
+		os.Setenv("ENBUILD_MAX_RESPONSE_BYTES", strconv.Itoa(*maxResponseBytes))
+	}
+	if *freshnessWindow > 0 {
+		os.Setenv("ENBUILD_FRESHNESS_WINDOW", freshnessWindow.String())
+	}
+	if *catalogURLTemplate != "" {
+		os.Setenv("ENBUILD_CATALOG_URL_TEMPLATE", *catalogURLTemplate)
+	}
+	if *rateLimit > 0 {
+		os.Setenv("ENBUILD_RATE_LIMIT", strconv.FormatFloat(*rateLimit, 'f', -1, 64))
+	}
+	if *rateBurst > 0 {
+		os.Setenv("ENBUILD_RATE_BURST", strconv.Itoa(*rateBurst))
+	}
+	if *timeout < 0 {
+		fatalf("Error: --timeout must not be negative")
+	}
+	if *timeout > 0 {
+		os.Setenv("ENBUILD_TIMEOUT", timeout.String())
+	}
+	if *maxRetryWait < 0 {
+		fatalf("Error: --max-retry-wait must not be negative")
+	}
+	// 0s disables retries, so an explicit flag is told apart from the default.
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "max-retry-wait" {
+			os.Setenv("ENBUILD_MAX_RETRY_WAIT", maxRetryWait.String())
+		}
+	})
+	if *maxIdleConns < 0 || *maxIdleConnsPerHost < 0 || *idleConnTimeout < 0 {
+		fatalf("Error: --max-idle-conns, --max-idle-conns-per-host, and --idle-conn-timeout must not be negative")
+	}
+	if *maxIdleConns > 0 {
+		os.Setenv("ENBUILD_MAX_IDLE_CONNS", strconv.Itoa(*maxIdleConns))
+	}
+	if *maxIdleConnsPerHost > 0 {
+		os.Setenv("ENBUILD_MAX_IDLE_CONNS_PER_HOST", strconv.Itoa(*maxIdleConnsPerHost))
+	}
+	if *idleConnTimeout > 0 {
+		os.Setenv("ENBUILD_IDLE_CONN_TIMEOUT", idleConnTimeout.String())
+	}
+	if *cacheTTL > 0 {
+		os.Setenv("ENBUILD_CACHE_TTL", cacheTTL.String())
+	}
+
+	if *proxy != "" {
+		os.Setenv("ENBUILD_PROXY", *proxy)
+	}
+	if *auditLogPath != "" {
+		os.Setenv("ENBUILD_AUDIT_LOG", *auditLogPath)
+	}
+	if *readOnly {
+		os.Setenv("ENBUILD_READ_ONLY", "true")
+	}
+	if len(disabledTools) > 0 {
+		if env := os.Getenv("ENBUILD_DISABLED_TOOLS"); env != "" {
+			disabledTools = append(disabledTools, env)
+		}
+		os.Setenv("ENBUILD_DISABLED_TOOLS", disabledTools.String())
+	}
+	if len(headers) > 0 {
+		// Flag values are kept whole, one per line, since they may contain
+		// commas; see headerEntries.
+		entries := append([]string(headers), headerEntries(os.Getenv("ENBUILD_HEADERS"))...)
+		os.Setenv("ENBUILD_HEADERS", strings.Join(entries, "\n")+"\n")
+	}
+	if *tokenCache {
+		os.Setenv("ENBUILD_TOKEN_CACHE", "true")
+	}
+	if *insecure {
+		os.Setenv("ENBUILD_INSECURE_SKIP_VERIFY", "true")
+	}
+
+	if *selfTest {
+		os.Exit(runSelfTest())
+	}
+
+	configureInsecureTLS()
+
+	ec.warnPasswordFlag()
+	if err := ec.resolvePassword(os.Stdin); err != nil {
+		fatalf("Error: %v", err)
+	}
+
+	if ec.secretRef != "" {
+		username, password, err := resolveSecretRef(ec.secretRef)
+		if err != nil {
+			fatalf("Error: %v", err)
+		}
+		if ec.username == "" {
+			ec.username = username
+		}
+		if ec.password == "" {
+			ec.password = password
+		}
+	}
+
+	// The base URL flag has a default, so only an explicit flag may override
+	// the environment or config file.
+	baseURLFlagSet := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "base-url" {
+			baseURLFlagSet = true
+		}
+	})
+	var cfg fileConfig
+	if ec.config != "" {
+		var err error
+		if cfg, err = loadConfigFile(ec.config, ec.profile); err != nil {
+			fatalf("Error: %v", err)
+		}
+	} else if ec.profile != "" {
+		fatalf("Error: --profile requires --config")
+	}
+	// --env stands in for --base-url, and ENBUILD_ENV for ENBUILD_BASE_URL,
+	// so both keep the usual flags > env vars > config file order.
+	if ec.env != "" {
+		if baseURLFlagSet {
+			fatalf("Error: use only one of --base-url and --env")
+		}
+		baseURL, err := resolveEnvironment(ec.env, cfg.Environments)
+		if err != nil {
+			fatalf("Error: %v", err)
+		}
+		ec.baseURL = baseURL
+		baseURLFlagSet = true
+	} else if env := os.Getenv("ENBUILD_ENV"); env != "" && os.Getenv("ENBUILD_BASE_URL") == "" {
+		baseURL, err := resolveEnvironment(env, cfg.Environments)
+		if err != nil {
+			fatalf("Error: ENBUILD_ENV: %v", err)
+		}
+		os.Setenv("ENBUILD_BASE_URL", baseURL)
+	}
+	if ec.config != "" {
+		cfg.applyEnv(ec, baseURLFlagSet)
+	}
+	if err := configureBackends(cfg.Backends); err != nil {
+		fatalf("Error: config file %s: %v", ec.config, err)
+	}
+	if raw := os.Getenv("ENBUILD_TIMEOUT"); raw != "" {
+		if d, err := time.ParseDuration(raw); err != nil || d < 0 {
+			fatalf("Error: ENBUILD_TIMEOUT must be a non-negative duration such as 30s, got %q", raw)
+		}
+	}
+	if raw := os.Getenv("ENBUILD_MAX_RETRY_WAIT"); raw != "" {
+		if d, err := time.ParseDuration(raw); err != nil || d < 0 {
+			fatalf("Error: ENBUILD_MAX_RETRY_WAIT must be a non-negative duration such as 10s, got %q", raw)
+		}
+	}
+	if !baseURLFlagSet && os.Getenv("ENBUILD_BASE_URL") != "" {
+		ec.baseURL = ""
+	}
+
+	// Retrieve credentials and baseURL, set them as environment variables.
+	// A token or Keycloak client credentials replace username/password.
+	if ec.token != "" {
+		os.Setenv("ENBUILD_TOKEN", ec.token)
+	}
+	if ec.clientID != "" {
+		os.Setenv("ENBUILD_CLIENT_ID", ec.clientID)
+	}
+	if ec.clientSecret != "" {
+		os.Setenv("ENBUILD_CLIENT_SECRET", ec.clientSecret)
+	}
+	// stdio serves a single client, so it needs credentials up front. SSE
+	// and HTTP may start without them when every caller passes its own;
+	// getCredentials rejects calls that do not.
+	requireCredential := setEnvOrExit
+	if transport != "stdio" {
+		requireCredential = setEnvIfPresent
+	}
+	if os.Getenv("ENBUILD_TOKEN") == "" && (os.Getenv("ENBUILD_CLIENT_ID") != "" || os.Getenv("ENBUILD_CLIENT_SECRET") != "") {
+		requireCredential("ENBUILD_CLIENT_ID", "", "--client-id flag")
+		requireCredential("ENBUILD_CLIENT_SECRET", "", "--client-secret flag")
+	} else if os.Getenv("ENBUILD_TOKEN") == "" {
+		requireCredential("ENBUILD_USERNAME", ec.username, "--username flag")
+		requireCredential("ENBUILD_PASSWORD", ec.password, "--password flag")
+	}
 	setEnvOrExit("ENBUILD_BASE_URL", ec.baseURL, "--base-url flag")
+	baseURL, err := normalizeBaseURL(os.Getenv("ENBUILD_BASE_URL"))
+	if err != nil {
+		fatalf("Error: %v", err)
+	}
+	os.Setenv("ENBUILD_BASE_URL", baseURL)
 
-	if err := run(transport, *addr, *logLevel, ec); err != nil {
-		log.Fatalf("Error: %v", err)
+	if *authToken == "" {
+		*authToken = os.Getenv("ENBUILD_AUTH_TOKEN")
+	}
+
+	if err := run(transport, *addr, normalizeBasePath(*basePath), *logLevel, *authToken, *maxRequestBytes, *metrics, ec); err != nil {
+		fatalf("Error: %v", err)
 	}
 }
 
@@ -113,114 +968,425 @@ func setEnvOrExit(envVar, value, flagName string) {
 	if value == "" {
 		value = os.Getenv(envVar)
 		if value == "" {
-			log.Fatalf("Error: ENVBUILD %s is required. Provide it via %s or %s environment variable", envVar, flagName, envVar)
+			fatalf("Error: ENVBUILD %s is required. Provide it via %s or %s environment variable", envVar, flagName, envVar)
 		}
 	}
 	os.Setenv(envVar, value)
 }
 
+// setEnvIfPresent is setEnvOrExit for settings that may be left unset.
+func setEnvIfPresent(envVar, value, flagName string) {
+	if value != "" {
+		os.Setenv(envVar, value)
+	}
+}
+
+// hasStartupCredentials reports whether the server has complete credentials
+// of its own for calls that do not pass any.
+func hasStartupCredentials() bool {
+	return os.Getenv("ENBUILD_TOKEN") != "" ||
+		(os.Getenv("ENBUILD_CLIENT_ID") != "" && os.Getenv("ENBUILD_CLIENT_SECRET") != "") ||
+		(os.Getenv("ENBUILD_USERNAME") != "" && os.Getenv("ENBUILD_PASSWORD") != "")
+}
+
 func prepareClientOptions(baseURL, username, password string) []enbuild.ClientOption {
 	debug := false
 	if os.Getenv("ENBUILD_DEBUG") == "true" {
 		debug = true
 	}
-	return []enbuild.ClientOption{
+	options := []enbuild.ClientOption{
 		enbuild.WithDebug(debug),
 		enbuild.WithBaseURL(baseURL),
-		enbuild.WithKeycloakAuth(username, password),
 	}
+	// A zero or unset timeout keeps the SDK's default.
+	if timeout, err := time.ParseDuration(os.Getenv("ENBUILD_TIMEOUT")); err == nil && timeout > 0 {
+		options = append(options, enbuild.WithTimeout(timeout))
+	}
+	return append(options, enbuild.WithKeycloakAuth(username, password))
+}
+
+// credentials are the ENBUILD connection settings resolved for one tool
+// call. Exactly one way of authenticating is set: Token, ClientID and
+// ClientSecret, or Username and Password.
+type credentials struct {
+	BaseURL      string
+	Username     string
+	Password     string
+	Token        string
+	ClientID     string
+	ClientSecret string
+}
+
+// getCredentials resolves the credentials for a tool call. Credentials
+// passed as arguments replace the server's entirely: a partial set is an
+// error rather than being completed from the environment, and the server's
+// credentials are never sent to a base_url other than its own, so a call
+// cannot mix one tenant's credentials with another's. A backend argument
+// selects a configured backend, whose credentials stand in for the server's.
+func getCredentials(request mcp.CallToolRequest) (credentials, error) {
+	var creds credentials
+	creds.Username, _ = request.GetArguments()["username"].(string)
+	creds.Password, _ = request.GetArguments()["password"].(string)
+	creds.Token, _ = request.GetArguments()["token"].(string)
+	creds.ClientID, _ = request.GetArguments()["client_id"].(string)
+	creds.ClientSecret, _ = request.GetArguments()["client_secret"].(string)
+	creds.BaseURL, _ = request.GetArguments()["base_url"].(string)
+	baseURLArgument := creds.BaseURL != ""
+	var backend *backendConfig
+	if name, _ := request.GetArguments()["backend"].(string); name != "" {
+		if baseURLArgument {
+			return credentials{}, fmt.Errorf("pass either backend or base_url, not both")
+		}
+		b, err := lookupBackend(name)
+		if err != nil {
+			return credentials{}, err
+		}
+		backend = &b
+		creds.BaseURL = b.BaseURL
+	}
+	if creds.BaseURL == "" {
+		creds.BaseURL = os.Getenv("ENBUILD_BASE_URL")
+	}
+	if creds.BaseURL != "" {
+		normalized, err := normalizeBaseURL(creds.BaseURL)
+		if err != nil {
+			return credentials{}, err
+		}
+		creds.BaseURL = normalized
+	}
+	userCreds := creds.Username != "" || creds.Password != ""
+	clientCreds := creds.ClientID != "" || creds.ClientSecret != ""
+	if creds.Token == "" && userCreds && (creds.Username == "" || creds.Password == "") {
+		return credentials{}, fmt.Errorf("Incomplete credentials: pass both username and password, or neither to use the server's credentials")
+	}
+	if creds.Token == "" && clientCreds && (creds.ClientID == "" || creds.ClientSecret == "") {
+		return credentials{}, fmt.Errorf("Incomplete credentials: pass both client_id and client_secret, or neither to use the server's credentials")
+	}
+
+	if creds.Token == "" && !userCreds && !clientCreds && backend != nil {
+		creds.Token = backend.Token
+		creds.ClientID, creds.ClientSecret = backend.ClientID, backend.ClientSecret
+		creds.Username, creds.Password = backend.Username, backend.Password
+		clientCreds = creds.ClientID != ""
+	} else if creds.Token == "" && !userCreds && !clientCreds {
+		if baseURLArgument {
+			if startup, err := normalizeBaseURL(os.Getenv("ENBUILD_BASE_URL")); err != nil || startup != creds.BaseURL {
+				return credentials{}, fmt.Errorf("Missing required credentials: base_url %s is not the server's base URL, so pass a token, client_id and client_secret, or username and password for it", creds.BaseURL)
+			}
+		}
+		if !hasStartupCredentials() {
+			return credentials{}, fmt.Errorf("Missing required credentials: this server was started without credentials, so pass a token, client_id and client_secret, or username and password")
+		}
+		creds.Token = os.Getenv("ENBUILD_TOKEN")
+		if creds.Token == "" {
+			creds.ClientID = os.Getenv("ENBUILD_CLIENT_ID")
+			creds.ClientSecret = os.Getenv("ENBUILD_CLIENT_SECRET")
+			clientCreds = creds.ClientID != "" || creds.ClientSecret != ""
+		}
+		if creds.Token == "" && !clientCreds {
+			creds.Username = os.Getenv("ENBUILD_USERNAME")
+			creds.Password = os.Getenv("ENBUILD_PASSWORD")
+		}
+	}
+	if creds.Token != "" {
+		if creds.BaseURL == "" {
+			return credentials{}, fmt.Errorf("Missing required credentials: baseURL")
+		}
+		// A token takes precedence over client credentials and username/password.
+		return credentials{BaseURL: creds.BaseURL, Token: creds.Token}, nil
+	}
+	if clientCreds {
+		if creds.BaseURL == "" || creds.ClientID == "" || creds.ClientSecret == "" {
+			return credentials{}, fmt.Errorf("Missing required credentials: baseURL and both client_id and client_secret")
+		}
+		// Client credentials take precedence over username/password.
+		return credentials{BaseURL: creds.BaseURL, ClientID: creds.ClientID, ClientSecret: creds.ClientSecret}, nil
+	}
+	if creds.BaseURL == "" || creds.Username == "" || creds.Password == "" {
+		return credentials{}, fmt.Errorf("Missing required credentials: baseURL and either a token or username and password")
+	}
+	return creds, nil
 }
 
-func getCredentials(request mcp.CallToolRequest) (string, string, string, error) {
-	username, _ := request.Params.Arguments["username"].(string)
-	password, _ := request.Params.Arguments["password"].(string)
-	baseURL, _ := request.Params.Arguments["base_url"].(string)
-	if baseURL == "" {
-		baseURL = os.Getenv("ENBUILD_BASE_URL")
+// normalizeBaseURL checks that raw is an absolute http(s) URL and returns it
+// without trailing slashes or the API version path, which the clients add,
+// so "https://host/", "https://host", and "https://host/enbuild-bk/api/v1/"
+// all behave the same.
+func normalizeBaseURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid base URL %q: must be an absolute http or https URL such as https://enbuild.example.com", raw)
 	}
-	if username == "" {
-		username = os.Getenv("ENBUILD_USERNAME")
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.Path = strings.TrimSuffix(u.Path, strings.TrimSuffix(apiVersionPath, "/"))
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String(), nil
+}
+
+// getIntArgument reads an optional non-negative integer argument. MCP
+// clients differ in how they send numbers, so see coerceInt for the forms
+// accepted. A missing or empty argument is 0.
+func getIntArgument(request mcp.CallToolRequest, name string) (int, error) {
+	n, err := coerceInt(name, request.GetArguments()[name])
+	if err != nil {
+		return 0, err
 	}
-	if password == "" {
-		password = os.Getenv("ENBUILD_PASSWORD")
+	if n < 0 {
+		return 0, fmt.Errorf("%s must not be negative", name)
 	}
-	if baseURL == "" || username == "" || password == "" {
-		return "", "", "", fmt.Errorf("Missing required credentials: baseURL, username, or password")
+	return n, nil
+}
+
+// coerceInt converts an integer argument sent as a JSON number (float64,
+// or json.Number when decoded with UseNumber), a Go int, or a numeric
+// string such as "10". Fractions and non-numeric strings are errors. nil
+// and "" are 0.
+func coerceInt(name string, value interface{}) (int, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v != math.Trunc(v) || math.IsInf(v, 0) {
+			return 0, fmt.Errorf("%s must be a whole number, got %v", name, v)
+		}
+		return int(v), nil
+	case json.Number:
+		return coerceInt(name, v.String())
+	case string:
+		s := strings.TrimSpace(v)
+		if s == "" {
+			return 0, nil
+		}
+		if n, err := strconv.Atoi(s); err == nil {
+			return n, nil
+		}
+		// "10.0" is accepted like the number 10.
+		if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsNaN(f) {
+			return coerceInt(name, f)
+		}
+		return 0, fmt.Errorf("%s must be an integer, got %q", name, v)
+	default:
+		return 0, fmt.Errorf("%s must be an integer, got %T", name, value)
 	}
-	return baseURL, username, password, nil
 }
 
-func searchCatalogs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	catalogVCS, _ := request.Params.Arguments["vcs"].(string)
-	catalogName, _ := request.Params.Arguments["name"].(string)
-	catalogType, _ := request.Params.Arguments["type"].(string)
+// vcsArgument declares the vcs argument of the search tools. It is only
+// required when no ENBUILD_DEFAULT_VCS is configured to fall back to.
+func vcsArgument() mcp.ToolOption {
+	if vcs := os.Getenv("ENBUILD_DEFAULT_VCS"); vcs != "" {
+		return mcp.WithString("vcs", mcp.Description(fmt.Sprintf("VCS to filter by (%s to search every VCS in parallel); defaults to %s", vcsChoices(allVCS), strings.ToUpper(vcs))))
+	}
+	return mcp.WithString("vcs", mcp.Description(fmt.Sprintf("VCS to filter by (%s to search every VCS in parallel)", vcsChoices(allVCS))), mcp.Required())
+}
 
+// maxCatalogNameLength bounds the name filter. Catalog names are short;
+// anything longer is almost certainly text pasted into the wrong argument.
+const maxCatalogNameLength = 256
+
+func validateCatalogName(name string) error {
+	if n := utf8.RuneCountInString(name); n > maxCatalogNameLength {
+		return fmt.Errorf("name must be at most %d characters, got %d", maxCatalogNameLength, n)
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("name must not contain control characters such as newlines or tabs")
+		}
+	}
+	return nil
+}
+
+func getSearchParams(request mcp.CallToolRequest) (*enbuild.CatalogListOptions, string, error) {
+	catalogVCS, _ := request.GetArguments()["vcs"].(string)
+	catalogName, _ := request.GetArguments()["name"].(string)
+	catalogType, _ := request.GetArguments()["type"].(string)
+
+	if catalogVCS == "" {
+		catalogVCS = os.Getenv("ENBUILD_DEFAULT_VCS")
+	}
 	if catalogVCS == "" {
-		return formatErrorResponse("Missing required parameter", fmt.Errorf("VCS parameter is required (GITHUB or GITLAB)"))
+		return nil, "Missing required parameter", fmt.Errorf("VCS parameter is required (%s)", vcsChoices(allVCS))
+	}
+
+	if err := validateCatalogName(catalogName); err != nil {
+		return nil, "Invalid name value", err
 	}
 
 	catalogVCS = strings.ToUpper(catalogVCS)
 
-	if catalogVCS != "GITHUB" && catalogVCS != "GITLAB" {
-		return formatErrorResponse("Invalid VCS value", fmt.Errorf("VCS must be either GITHUB or GITLAB"))
+	if !isSupportedVCS(catalogVCS) && catalogVCS != allVCS {
+		return nil, "Invalid VCS value", fmt.Errorf("VCS must be %s", vcsChoices(allVCS))
 	}
 
-	baseURL, username, password, err := getCredentials(request)
+	return &enbuild.CatalogListOptions{
+		VCS:  catalogVCS,
+		Name: catalogName,
+		Type: normalizeCatalogTypeFilter(catalogType),
+	}, "", nil
+}
+
+func searchCatalogs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resumeToken, _ := request.GetArguments()["resume_token"].(string)
+
+	opts, problem, err := getSearchParams(request)
 	if err != nil {
-		return formatErrorResponse("Missing credentials", err)
+		return formatValidationErrorResponse(problem, err)
 	}
 
-	client, err := initializeClient(baseURL, username, password)
+	limit, err := getIntArgument(request, "limit")
 	if err != nil {
-		return formatErrorResponse("Failed to initialize ENBUILD client", err)
+		return formatValidationErrorResponse("Invalid limit value", err)
+	}
+	offset, err := getIntArgument(request, "offset")
+	if err != nil {
+		return formatValidationErrorResponse("Invalid offset value", err)
+	}
+	sortField, sortOrder, err := getSortParams(request)
+	if err != nil {
+		return formatValidationErrorResponse("Invalid sort value", err)
+	}
+	match, err := getMatchParam(request)
+	if err != nil {
+		return formatValidationErrorResponse("Invalid match value", err)
 	}
 
-	opts := &enbuild.CatalogListOptions{
-		VCS:  catalogVCS,
-		Name: catalogName,
-		Type: catalogType,
+	creds, err := getCredentials(request)
+	if err != nil {
+		return formatCredentialsErrorResponse(err)
 	}
 
-	catalogs, err := client.Catalogs.List(opts)
+	tokenKey := resumeTokenKey(creds)
+	if resumeToken != "" {
+		pos, err := decodeResumeToken(tokenKey, resumeToken)
+		if err != nil {
+			return formatTypedErrorResponse(errorTypeValidation, errorCodeInvalidToken, "Invalid resume token", err)
+		}
+		if pos.VCS != opts.VCS || pos.Name != opts.Name || pos.Type != opts.Type || pos.Sort != sortField+" "+sortOrder || pos.Match != match {
+			return formatTypedErrorResponse(errorTypeValidation, errorCodeInvalidToken, "Invalid resume token", fmt.Errorf("token was issued for different search filters or sort"))
+		}
+		offset = pos.Offset
+		if limit == 0 {
+			limit = pos.Limit
+		}
+	}
+
+	client, err := initializeClient(ctx, creds)
+	if err != nil {
+		return formatErrorResponse("Failed to initialize ENBUILD client", err)
+	}
+
+	catalogs, warning, err := listFilteredCatalogs(ctx, client, opts)
 	if err != nil {
-		return formatErrorResponse("Failed to list catalogs", err)
+		return formatCallErrorResponse("Failed to list catalogs", err)
+	}
+
+	catalogs = filterByName(catalogs, opts.Name, match)
+	sortCatalogs(catalogs, sortField, sortOrder)
+
+	total := len(catalogs)
+	found := total > 0
+	if total == 0 {
+		message := fmt.Sprintf("No catalogs matched the filters %s; the search succeeded but found nothing", describeFilters(opts.VCS, opts.Name, match, opts.Type))
+		if opts.Type != "" {
+			message += "; list_catalog_types lists the types in use"
+		}
+		response := CatalogResponse{
+			Success: true,
+			Found:   &found,
+			Data:    []*enbuild.Catalog{},
+			Message: message + warning,
+		}
+		return formatJSONResponse(response)
+	}
+	if offset > 0 && offset >= total {
+		response := CatalogResponse{
+			Success: true,
+			Total:   total,
+			Found:   &found,
+			Data:    []*enbuild.Catalog{},
+			Message: fmt.Sprintf("Offset %d is past the end of the %d matching catalogs for VCS: %s", offset, total, opts.VCS) + warning,
+		}
+		return formatJSONResponse(response)
+	}
+
+	page := resumePosition{
+		Offset: offset,
+		Limit:  limit,
+		VCS:    opts.VCS,
+		Name:   opts.Name,
+		Type:   opts.Type,
+		Sort:   sortField + " " + sortOrder,
+		Match:  match,
 	}
+	recordPage(ctx, tokenKey, page)
+
+	var next string
+	catalogs = catalogs[offset:]
+	if limit > 0 {
+		end := limit
+		if end < len(catalogs) {
+			pos := page
+			pos.Offset = offset + end
+			next, err = encodeResumeToken(tokenKey, pos)
+			if err != nil {
+				return nil, fmt.Errorf("error creating resume token: %v", err)
+			}
+		} else {
+			end = len(catalogs)
+		}
+		catalogs = catalogs[:end]
+	}
+
+	message := fmt.Sprintf("Successfully retrieved %d catalogs for VCS: %s", len(catalogs), opts.VCS)
+	if len(catalogs) < total {
+		message = fmt.Sprintf("Successfully retrieved %d of %d catalogs (offset %d) for VCS: %s", len(catalogs), total, offset, opts.VCS)
+	}
+	message += warning
 
 	response := CatalogResponse{
-		Success: true,
-		Count:   len(catalogs),
-		Data:    catalogs,
-		Message: fmt.Sprintf("Successfully retrieved %d catalogs for VCS: %s", len(catalogs), catalogVCS),
+		Success:     true,
+		Count:       len(catalogs),
+		Total:       total,
+		Found:       &found,
+		Data:        withWebURLs(creds.BaseURL, catalogs),
+		ResumeToken: next,
+		Message:     message,
 	}
 
 	return formatJSONResponse(response)
 }
 
 func getCatalogDetails(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	id, ok := request.Params.Arguments["id"].(string)
+	id, ok := request.GetArguments()["id"].(string)
 	if !ok || id == "" {
-		return formatErrorResponse("Missing required parameter", fmt.Errorf("catalog ID is required"))
+		return formatValidationErrorResponse("Missing required parameter", fmt.Errorf("catalog ID is required"))
 	}
 
-	baseURL, username, password, err := getCredentials(request)
+	creds, err := getCredentials(request)
 	if err != nil {
-		return formatErrorResponse("Missing credentials", err)
+		return formatCredentialsErrorResponse(err)
 	}
 
-	client, err := initializeClient(baseURL, username, password)
+	client, err := initializeClient(ctx, creds)
 	if err != nil {
 		return formatErrorResponse("Failed to initialize ENBUILD client", err)
 	}
 
-	catalog, err := client.Catalogs.Get(id, &enbuild.CatalogListOptions{})
+	catalog, err := getCatalogContext(ctx, client, id, &enbuild.CatalogListOptions{})
 	if err != nil {
-		return formatErrorResponse("Failed to get catalog details", err)
+		return formatCallErrorResponse("Failed to get catalog details", err)
 	}
 
 	response := CatalogResponse{
 		Success: true,
 		Count:   1,
-		Data:    catalog,
+		Data:    withWebURL(creds.BaseURL, catalog),
 		Message: fmt.Sprintf("Successfully retrieved details for catalog ID: %s", id),
 	}
 
@@ -228,6 +1394,7 @@ func getCatalogDetails(ctx context.Context, request mcp.CallToolRequest) (*mcp.C
 }
 
 func formatJSONResponse(response CatalogResponse) (*mcp.CallToolResult, error) {
+	response.APIVersion = responseAPIVersion
 	jsonData, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
 		return nil, fmt.Errorf("error formatting JSON response: %v", err)
@@ -237,9 +1404,22 @@ func formatJSONResponse(response CatalogResponse) (*mcp.CallToolResult, error) {
 }
 
 func formatErrorResponse(message string, err error) (*mcp.CallToolResult, error) {
+	return formatErrorCodeResponse("", message, err)
+}
+
+func formatErrorCodeResponse(code, message string, err error) (*mcp.CallToolResult, error) {
+	return formatTypedErrorResponse(inferErrorType(err), code, message, err)
+}
+
+func formatTypedErrorResponse(errorType, code, message string, err error) (*mcp.CallToolResult, error) {
 	response := CatalogResponse{
-		Success: false,
-		Message: fmt.Sprintf("%s: %v", message, err),
+		APIVersion: responseAPIVersion,
+		Success:    false,
+		Message:    fmt.Sprintf("%s: %v", message, err),
+		ErrorType:  errorType,
+		ErrorCode:  code,
+		StatusCode: statusCodeFromError(err),
+		RetryAfter: retryAfterFromError(err),
 	}
 
 	jsonData, err := json.MarshalIndent(response, "", "  ")
@@ -247,10 +1427,96 @@ func formatErrorResponse(message string, err error) (*mcp.CallToolResult, error)
 		return nil, fmt.Errorf("error formatting error response: %v", err)
 	}
 
-	return mcp.NewToolResultText(string(jsonData)), nil
+	// The JSON body is kept for clients that only read the text; IsError
+	// lets conformant clients surface the failure as a tool error.
+	return mcp.NewToolResultError(string(jsonData)), nil
 }
 
-func initializeClient(baseURL, username, password string) (*enbuild.Client, error) {
-	options := prepareClientOptions(baseURL, username, password)
-	return enbuild.NewClient(options...)
+// apiErrorStatus matches the "API error: <status>" text that the SDK and
+// the token client return for non-2xx responses.
+var apiErrorStatus = regexp.MustCompile(`API error: (\d{3})\b`)
+
+// statusCodeFromError returns the HTTP status code of a failed ENBUILD
+// call, or 0 if err did not come from an HTTP response. The SDK wraps its
+// errors with %v, so the code is parsed from the message.
+func statusCodeFromError(err error) int {
+	if err == nil {
+		return 0
+	}
+	m := apiErrorStatus.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 0
+	}
+	code, _ := strconv.Atoi(m[1])
+	return code
+}
+
+// CatalogService is the part of the ENBUILD catalog API used by the tool
+// handlers. It is satisfied by the SDK's *enbuild.Service.
+type CatalogService interface {
+	List(opts ...*enbuild.CatalogListOptions) ([]*enbuild.Catalog, error)
+	Get(id string, opts *enbuild.CatalogListOptions) (*enbuild.Catalog, error)
+}
+
+// newCatalogService builds the catalog service used by the handlers. It is a
+// variable so that --self-test can substitute an in-memory implementation.
+var newCatalogService = func(creds credentials) (CatalogService, error) {
+	if creds.Token != "" {
+		return newTokenCatalogService(creds.BaseURL, creds.Token)
+	}
+	diskKey := diskTokenCacheKey(creds)
+	if creds.ClientID != "" {
+		return newClientCredentialsCatalogService(creds.BaseURL, creds.ClientID, creds.ClientSecret, diskKey)
+	}
+	if diskKey != "" {
+		// The SDK keeps its token private, so with --token-cache logins go
+		// through our own password grant instead of WithKeycloakAuth.
+		return newPasswordCatalogService(creds.BaseURL, creds.Username, creds.Password, diskKey)
+	}
+	options := prepareClientOptions(creds.BaseURL, creds.Username, creds.Password)
+	client, err := enbuild.NewClient(options...)
+	if err != nil {
+		return nil, err
+	}
+	return client.Catalogs, nil
+}
+
+// initializeClient builds the catalog service for one tool call on top of
+// the shared client for creds. ctx bounds any wait for the outbound rate
+// limiter; cached results skip the limiter.
+func initializeClient(ctx context.Context, creds credentials) (CatalogService, error) {
+	service, err := sharedCatalogService(creds)
+	if err != nil {
+		return nil, err
+	}
+	if b, ok := service.(contextBinder); ok {
+		service = b.withContext(ctx)
+	}
+	if limiter := outboundLimiter(); limiter != nil {
+		service = rateLimitedCatalogService{CatalogService: service, ctx: ctx, limiter: limiter}
+	}
+	if ttl := cacheTTL(); ttl > 0 {
+		service = newCachedCatalogService(service, creds, ttl)
+	}
+	if os.Getenv("ENBUILD_DETERMINISTIC_ORDER") == "true" {
+		service = sortedCatalogService{service}
+	}
+	return service, nil
+}
+
+// sortedCatalogService orders List results by catalog ID so output does not
+// depend on the API's ordering.
+type sortedCatalogService struct {
+	CatalogService
+}
+
+func (s sortedCatalogService) List(opts ...*enbuild.CatalogListOptions) ([]*enbuild.Catalog, error) {
+	catalogs, err := s.CatalogService.List(opts...)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(catalogs, func(i, j int) bool {
+		return fmt.Sprint(catalogs[i].ID) < fmt.Sprint(catalogs[j].ID)
+	})
+	return catalogs, nil
 }
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the tool call latency
+// histogram. They match the Prometheus client defaults.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type latencyHistogram struct {
+	counts []uint64 // per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// toolMetrics records tool call counts and latencies in memory and renders
+// them in the Prometheus text exposition format.
+var toolMetrics = struct {
+	sync.Mutex
+	calls     map[[2]string]uint64 // {tool, outcome}
+	latencies map[string]*latencyHistogram
+}{
+	calls:     map[[2]string]uint64{},
+	latencies: map[string]*latencyHistogram{},
+}
+
+func recordToolCall(tool, outcome string, elapsed time.Duration) {
+	toolMetrics.Lock()
+	defer toolMetrics.Unlock()
+	toolMetrics.calls[[2]string{tool, outcome}]++
+
+	h, ok := toolMetrics.latencies[tool]
+	if !ok {
+		h = &latencyHistogram{counts: make([]uint64, len(latencyBuckets))}
+		toolMetrics.latencies[tool] = h
+	}
+	seconds := elapsed.Seconds()
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// instrumentTool wraps a tool handler to record its outcome and latency.
+func instrumentTool(name string, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := next(ctx, request)
+		outcome := "success"
+		if err != nil || result == nil || result.IsError {
+			outcome = "error"
+		}
+		recordToolCall(name, outcome, time.Since(start))
+		return result, err
+	}
+}
+
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	toolMetrics.Lock()
+	defer toolMetrics.Unlock()
+
+	var b strings.Builder
+	b.WriteString("# HELP enbuild_mcp_tool_calls_total Tool calls by tool and outcome.\n")
+	b.WriteString("# TYPE enbuild_mcp_tool_calls_total counter\n")
+	keys := make([][2]string, 0, len(toolMetrics.calls))
+	for k := range toolMetrics.calls {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	for _, k := range keys {
+		fmt.Fprintf(&b, "enbuild_mcp_tool_calls_total{tool=%q,outcome=%q} %d\n", k[0], k[1], toolMetrics.calls[k])
+	}
+
+	b.WriteString("# HELP enbuild_mcp_tool_call_duration_seconds Tool call latency.\n")
+	b.WriteString("# TYPE enbuild_mcp_tool_call_duration_seconds histogram\n")
+	tools := make([]string, 0, len(toolMetrics.latencies))
+	for tool := range toolMetrics.latencies {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	for _, tool := range tools {
+		h := toolMetrics.latencies[tool]
+		var cumulative uint64
+		for i, bound := range latencyBuckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(&b, "enbuild_mcp_tool_call_duration_seconds_bucket{tool=%q,le=\"%g\"} %d\n", tool, bound, cumulative)
+		}
+		fmt.Fprintf(&b, "enbuild_mcp_tool_call_duration_seconds_bucket{tool=%q,le=\"+Inf\"} %d\n", tool, h.count)
+		fmt.Fprintf(&b, "enbuild_mcp_tool_call_duration_seconds_sum{tool=%q} %g\n", tool, h.sum)
+		fmt.Fprintf(&b, "enbuild_mcp_tool_call_duration_seconds_count{tool=%q} %d\n", tool, h.count)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write([]byte(b.String()))
+}
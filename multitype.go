@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+// splitCatalogTypes splits a comma-separated type filter such as
+// "terraform,helm" into its distinct, non-empty types.
+func splitCatalogTypes(catalogType string) []string {
+	var types []string
+	seen := map[string]bool{}
+	for _, t := range strings.Split(catalogType, ",") {
+		t = strings.TrimSpace(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		types = append(types, t)
+	}
+	return types
+}
+
+// listCatalogsByTypes lists catalogs matching any of the comma-separated
+// types in opts.Type. The API filters by one type at a time, so each type
+// is listed separately and the results are merged, keeping the first
+// occurrence of each catalog ID. A filter without a comma is passed through
+// unchanged.
+func listCatalogsByTypes(ctx context.Context, client CatalogService, opts *enbuild.CatalogListOptions) ([]*enbuild.Catalog, error) {
+	if !strings.Contains(opts.Type, ",") {
+		return listCatalogsContext(ctx, client, opts)
+	}
+
+	types := splitCatalogTypes(opts.Type)
+	if len(types) == 0 {
+		anyType := *opts
+		anyType.Type = ""
+		return listCatalogsContext(ctx, client, &anyType)
+	}
+
+	var merged []*enbuild.Catalog
+	seen := map[string]bool{}
+	for _, t := range types {
+		typeOpts := *opts
+		typeOpts.Type = t
+		catalogs, err := listCatalogsContext(ctx, client, &typeOpts)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range catalogs {
+			id := fmt.Sprint(c.ID)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			merged = append(merged, c)
+		}
+	}
+	return merged, nil
+}
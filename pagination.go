@@ -0,0 +1,114 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const resumeTokenTTL = 24 * time.Hour
+
+// resumeTokenSecretFile holds the random key resume tokens are signed with
+// when ENBUILD_RESUME_TOKEN_SECRET is unset, relative to the user config
+// directory.
+const resumeTokenSecretFile = "enbuild-mcp-server/resume-token.key"
+
+type resumePosition struct {
+	Offset  int    `json:"o"`
+	Limit   int    `json:"l"`
+	VCS     string `json:"v"`
+	Name    string `json:"n,omitempty"`
+	Type    string `json:"t,omitempty"`
+	Sort    string `json:"s,omitempty"`
+	Match   string `json:"m,omitempty"`
+	Expires int64  `json:"e"`
+}
+
+// resumeTokenKey returns the key used to sign resume tokens for creds. It
+// comes from ENBUILD_RESUME_TOKEN_SECRET or a random key persisted in the
+// config directory, so tokens stay valid across server restarts, and never
+// from the password or tokens, so a resume token cannot be used to check
+// guesses at them. The base URL and user only keep callers apart.
+func resumeTokenKey(creds credentials) []byte {
+	secret := []byte(os.Getenv("ENBUILD_RESUME_TOKEN_SECRET"))
+	if len(secret) == 0 {
+		secret = resumeTokenSecret()
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("enbuild-resume-token\x00" + creds.BaseURL + "\x00" + creds.Username + "\x00" + creds.ClientID))
+	return mac.Sum(nil)
+}
+
+// generatedResumeSecret caches the persisted resume token key.
+var generatedResumeSecret struct {
+	sync.Mutex
+	key []byte
+}
+
+// resumeTokenSecret loads or creates the persisted key. If it cannot be
+// stored, a key for this process is used and tokens end with it.
+func resumeTokenSecret() []byte {
+	generatedResumeSecret.Lock()
+	defer generatedResumeSecret.Unlock()
+	if generatedResumeSecret.key != nil {
+		return generatedResumeSecret.key
+	}
+	key, err := localSecret(resumeTokenSecretFile)
+	if err != nil {
+		warnf("Resume tokens will not survive a restart: %v", err)
+		key = make([]byte, tokenCacheSecretSize)
+		rand.Read(key)
+	}
+	generatedResumeSecret.key = key
+	return key
+}
+
+func signResumePosition(key []byte, payload string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func encodeResumeToken(key []byte, pos resumePosition) (string, error) {
+	pos.Expires = time.Now().Add(resumeTokenTTL).Unix()
+	data, err := json.Marshal(pos)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + signResumePosition(key, payload), nil
+}
+
+func decodeResumeToken(key []byte, token string) (resumePosition, error) {
+	var pos resumePosition
+
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return pos, fmt.Errorf("malformed resume token")
+	}
+	if !hmac.Equal([]byte(signature), []byte(signResumePosition(key, payload))) {
+		return pos, fmt.Errorf("resume token signature does not match")
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return pos, fmt.Errorf("malformed resume token")
+	}
+	if err := json.Unmarshal(data, &pos); err != nil {
+		return pos, fmt.Errorf("malformed resume token")
+	}
+	if time.Now().Unix() > pos.Expires {
+		return pos, fmt.Errorf("resume token has expired")
+	}
+	if pos.Offset < 0 || pos.Limit <= 0 {
+		return pos, fmt.Errorf("resume token has an invalid position")
+	}
+	return pos, nil
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestResumeTokenKeyIgnoresPassword(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("ENBUILD_RESUME_TOKEN_SECRET", "")
+
+	alice := credentials{BaseURL: "https://enbuild.example.com", Username: "alice", Password: "one"}
+	key := resumeTokenKey(alice)
+	alice.Password = "two"
+	if !bytes.Equal(resumeTokenKey(alice), key) {
+		t.Error("resume token key depends on the password")
+	}
+	if bytes.Equal(resumeTokenKey(credentials{BaseURL: alice.BaseURL, Username: "bob"}), key) {
+		t.Error("different users share a resume token key")
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	errorCodeAuthFailed       = "AUTH_FAILED"
+	errorCodeConnectionFailed = "CONNECTION_FAILED"
+)
+
+// connectivityErrorMarkers and authErrorMarkers classify ENBUILD failures.
+// The SDK wraps errors with %v, so the underlying error types are lost and
+// only their text is left to inspect. Connectivity is checked first because
+// a Keycloak auth error can wrap a network error.
+var (
+	connectivityErrorMarkers = []string{
+		"dial tcp", "no such host", "connection refused", "connection reset",
+		"timeout", "deadline exceeded", "network connectivity", "tls:", "x509:", "eof",
+	}
+	authErrorMarkers = []string{
+		"401", "403", "unauthorized", "forbidden", "authentication with keycloak failed",
+		"no valid authentication token", "failed to refresh token", "invalid_grant",
+	}
+)
+
+func classifyPingError(err error) string {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return errorCodeConnectionFailed
+	}
+	msg := strings.ToLower(err.Error())
+	for _, marker := range connectivityErrorMarkers {
+		if strings.Contains(msg, marker) {
+			return errorCodeConnectionFailed
+		}
+	}
+	for _, marker := range authErrorMarkers {
+		if strings.Contains(msg, marker) {
+			return errorCodeAuthFailed
+		}
+	}
+	return ""
+}
+
+func pingErrorResponse(baseURL string, err error) (*mcp.CallToolResult, error) {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return formatCallErrorResponse("", err)
+	}
+	switch code := classifyPingError(err); code {
+	case errorCodeAuthFailed:
+		return formatErrorCodeResponse(code, fmt.Sprintf("Authentication failed for ENBUILD at %s; check the username/password, token, or client credentials", baseURL), err)
+	case errorCodeConnectionFailed:
+		return formatErrorCodeResponse(code, fmt.Sprintf("Could not connect to ENBUILD at %s; check the base URL and network connectivity", baseURL), err)
+	default:
+		return formatErrorResponse(fmt.Sprintf("ENBUILD at %s did not respond as expected", baseURL), err)
+	}
+}
+
+func ping(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	creds, err := getCredentials(request)
+	if err != nil {
+		return formatCredentialsErrorResponse(err)
+	}
+
+	// Username/password clients authenticate while being created, so
+	// failures here are reported the same way as a failed list call.
+	client, err := initializeClient(ctx, creds)
+	if err != nil {
+		return pingErrorResponse(creds.BaseURL, err)
+	}
+
+	// The API has no health or limit parameter; listing is the cheapest
+	// authenticated call available.
+	if _, err := listCatalogsContext(ctx, client); err != nil {
+		return pingErrorResponse(creds.BaseURL, err)
+	}
+
+	auth := "username/password"
+	switch {
+	case creds.Token != "":
+		auth = "token"
+	case creds.ClientID != "":
+		auth = "client_credentials"
+	}
+	response := CatalogResponse{
+		Success: true,
+		Data:    map[string]string{"base_url": creds.BaseURL, "auth": auth},
+		Message: fmt.Sprintf("Connected and authenticated to ENBUILD at %s", creds.BaseURL),
+	}
+
+	return formatJSONResponse(response)
+}
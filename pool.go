@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// configureConnectionPool applies ENBUILD_MAX_IDLE_CONNS,
+// ENBUILD_MAX_IDLE_CONNS_PER_HOST, and ENBUILD_IDLE_CONN_TIMEOUT to the
+// shared transport. Like --insecure it changes http.DefaultTransport in
+// place, because the SDK's clients cannot be given a transport of their
+// own. Unset values keep Go's defaults: 100 idle connections, 2 per host,
+// closed after 90s.
+func configureConnectionPool() error {
+	transport := defaultHTTPTransport()
+	for _, setting := range []struct {
+		envVar string
+		target *int
+	}{
+		{"ENBUILD_MAX_IDLE_CONNS", &transport.MaxIdleConns},
+		{"ENBUILD_MAX_IDLE_CONNS_PER_HOST", &transport.MaxIdleConnsPerHost},
+	} {
+		raw := os.Getenv(setting.envVar)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return fmt.Errorf("%s must be a non-negative integer, got %q", setting.envVar, raw)
+		}
+		*setting.target = n
+	}
+	if raw := os.Getenv("ENBUILD_IDLE_CONN_TIMEOUT"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d < 0 {
+			return fmt.Errorf("ENBUILD_IDLE_CONN_TIMEOUT must be a non-negative duration such as 90s, got %q", raw)
+		}
+		transport.IdleConnTimeout = d
+	}
+	debugf("Connection pool: %d idle connections, %d per host, idle timeout %s", transport.MaxIdleConns, maxIdleConnsPerHost(), transport.IdleConnTimeout)
+	return nil
+}
+
+// maxIdleConnsPerHost returns the effective per-host limit; the transport
+// treats 0 as http.DefaultMaxIdleConnsPerHost.
+func maxIdleConnsPerHost() int {
+	if n := defaultHTTPTransport().MaxIdleConnsPerHost; n > 0 {
+		return n
+	}
+	return http.DefaultMaxIdleConnsPerHost
+}
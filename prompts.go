@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+// registerPrompts adds prompt templates for common catalog workflows. Each
+// one points the model at the tools that carry out the workflow.
+func registerPrompts(s *server.MCPServer) {
+	s.AddPrompt(mcp.NewPrompt("find_module",
+		mcp.WithPromptDescription("Find catalogs matching a name and recommend the best fit"),
+		mcp.WithArgument("query", mcp.ArgumentDescription("Name or part of a name to search for"), mcp.RequiredArgument()),
+		mcp.WithArgument("vcs", mcp.ArgumentDescription("VCS to search ("+vcsChoices()+"); every VCS is searched when omitted")),
+		mcp.WithArgument("type", mcp.ArgumentDescription("Catalog type to filter by (e.g., terraform, ansible)")),
+	), findModulePrompt)
+	s.AddPrompt(mcp.NewPrompt("explain_catalog",
+		mcp.WithPromptDescription("Fetch a catalog and ask for a summary of what it deploys and how to use it"),
+		mcp.WithArgument("id", mcp.ArgumentDescription("ID of the catalog"), mcp.RequiredArgument()),
+	), explainCatalogPrompt)
+}
+
+func findModulePrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	query := strings.TrimSpace(request.Params.Arguments["query"])
+	if query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+
+	args := fmt.Sprintf("name=%q", query)
+	if t := strings.TrimSpace(request.Params.Arguments["type"]); t != "" {
+		args += fmt.Sprintf(", type=%q", t)
+	}
+	where := fmt.Sprintf("with vcs=%q to cover %s", allVCS, strings.Join(SupportedVCS, " and "))
+	if vcs := strings.ToUpper(strings.TrimSpace(request.Params.Arguments["vcs"])); vcs != "" {
+		where = fmt.Sprintf("with vcs=%q", vcs)
+	}
+
+	text := fmt.Sprintf(`Find ENBUILD catalogs that match %q.
+
+1. Call the search_catalogs tool with %s %s.
+2. If nothing matches, retry with a shorter or more general name, or use search_catalogs_regex with a pattern.
+3. List the matches with their name, type, version, and web_url, then recommend the best fit and explain why.
+4. Use get_catalog_details or get_catalog_readme on the recommended catalog if more detail is needed.`, query, args, where)
+
+	return mcp.NewGetPromptResult("Find a catalog module", []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+	}), nil
+}
+
+func explainCatalogPrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	id := strings.TrimSpace(request.Params.Arguments["id"])
+	if id == "" {
+		return nil, fmt.Errorf("id is required")
+	}
+
+	client, baseURL, err := resourceClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := getCatalogContext(ctx, client, id, &enbuild.CatalogListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get catalog %s: %v", id, err)
+	}
+	data, err := json.MarshalIndent(withWebURL(baseURL, catalog), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error formatting catalog %s: %v", id, err)
+	}
+
+	text := fmt.Sprintf(`Explain the ENBUILD catalog %q (ID %s), attached below.
+
+Summarize what it deploys, its type, VCS, and version, and the inputs a user must provide. Call get_catalog_readme with id=%q for its documentation, and offer to check a set of input values with validate_catalog_inputs.`, catalog.Name, id, id)
+
+	return mcp.NewGetPromptResult("Explain catalog "+catalog.Name, []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewEmbeddedResource(mcp.TextResourceContents{
+			URI:      strings.Replace(catalogResourceTemplate, "{id}", id, 1),
+			MIMEType: "application/json",
+			Text:     string(data),
+		})),
+	}), nil
+}
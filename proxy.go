@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// configureProxy routes outbound requests through ENBUILD_PROXY when it is
+// set. Otherwise http.DefaultTransport keeps honoring HTTP_PROXY,
+// HTTPS_PROXY, and NO_PROXY. Like --insecure, it changes the default
+// transport that the SDK, Keycloak, and token clients share.
+func configureProxy(baseURL string) error {
+	transport := defaultHTTPTransport()
+
+	if raw := os.Getenv("ENBUILD_PROXY"); raw != "" {
+		proxyURL, err := url.Parse(raw)
+		if err != nil {
+			return fmt.Errorf("invalid proxy URL: %v", err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return fmt.Errorf("invalid proxy URL %q: scheme must be http, https, socks5, or socks5h", proxyURL.Redacted())
+		}
+		if proxyURL.Host == "" {
+			return fmt.Errorf("invalid proxy URL %q: missing host", proxyURL.Redacted())
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+		debugf("Using proxy %s for ENBUILD requests", proxyURL.Redacted())
+		return nil
+	}
+
+	// Report the proxy the environment selects for the base URL, if any.
+	if req, err := http.NewRequest(http.MethodGet, baseURL, nil); err == nil {
+		if proxyURL, err := http.ProxyFromEnvironment(req); err == nil && proxyURL != nil {
+			debugf("Using proxy %s from the environment for ENBUILD requests", proxyURL.Redacted())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"math"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+// tokenBucket allows rate calls per second on average, with bursts of up
+// to burst calls.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = int(math.Max(1, math.Ceil(rate)))
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// wait blocks until a token is available or ctx is done.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// outboundLimiter is shared by every client so the limit applies to the
+// whole process. It is nil, meaning unlimited, unless ENBUILD_RATE_LIMIT
+// is a positive number of requests per second.
+var outboundLimiter = sync.OnceValue(func() *tokenBucket {
+	rate, err := strconv.ParseFloat(os.Getenv("ENBUILD_RATE_LIMIT"), 64)
+	if err != nil || rate <= 0 {
+		return nil
+	}
+	burst, _ := strconv.Atoi(os.Getenv("ENBUILD_RATE_BURST"))
+	return newTokenBucket(rate, burst)
+})
+
+// rateLimitedCatalogService waits for the outbound limiter before each API
+// call, giving up if the tool call's context is cancelled first.
+type rateLimitedCatalogService struct {
+	CatalogService
+	ctx     context.Context
+	limiter *tokenBucket
+}
+
+func (s rateLimitedCatalogService) List(opts ...*enbuild.CatalogListOptions) ([]*enbuild.Catalog, error) {
+	if err := s.limiter.wait(s.ctx); err != nil {
+		return nil, err
+	}
+	return s.CatalogService.List(opts...)
+}
+
+func (s rateLimitedCatalogService) Get(id string, opts *enbuild.CatalogListOptions) (*enbuild.Catalog, error) {
+	if err := s.limiter.wait(s.ctx); err != nil {
+		return nil, err
+	}
+	return s.CatalogService.Get(id, opts)
+}
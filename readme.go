@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+// readmeContentKeys are the content keys that may hold a catalog's README,
+// in order of preference. Keys are matched case-insensitively.
+var readmeContentKeys = []string{"readme", "readme.md", "documentation", "docs"}
+
+// catalogReadme returns the catalog's README text. The API has no README
+// endpoint, so it is read from the catalog content, falling back to the
+// description. It returns "" when the catalog has neither.
+func catalogReadme(catalog *enbuild.Catalog) string {
+	for _, want := range readmeContentKeys {
+		for key, value := range catalog.Content {
+			if !strings.EqualFold(key, want) {
+				continue
+			}
+			if text, ok := value.(string); ok && strings.TrimSpace(text) != "" {
+				return text
+			}
+		}
+	}
+	return strings.TrimSpace(catalog.Description)
+}
+
+func getCatalogReadme(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, ok := request.GetArguments()["id"].(string)
+	if !ok || id == "" {
+		return formatValidationErrorResponse("Missing required parameter", fmt.Errorf("catalog ID is required"))
+	}
+
+	creds, err := getCredentials(request)
+	if err != nil {
+		return formatCredentialsErrorResponse(err)
+	}
+
+	client, err := initializeClient(ctx, creds)
+	if err != nil {
+		return formatErrorResponse("Failed to initialize ENBUILD client", err)
+	}
+
+	catalog, err := getCatalogContext(ctx, client, id, &enbuild.CatalogListOptions{})
+	if err != nil {
+		return formatCallErrorResponse("Failed to get catalog", err)
+	}
+
+	readme := catalogReadme(catalog)
+	response := CatalogResponse{
+		Success: true,
+		Data:    readme,
+		Message: fmt.Sprintf("Successfully retrieved README for catalog ID: %s", id),
+	}
+	if readme == "" {
+		response.Message = fmt.Sprintf("No README found for catalog ID: %s", id)
+	} else {
+		response.Count = 1
+	}
+
+	return formatJSONResponse(response)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// readOnlyTool marks a tool as one that only reads from ENBUILD. mcp-go
+// assumes tools may modify state unless told otherwise, and --read-only
+// keeps only tools with this annotation.
+func readOnlyTool() mcp.ToolOption {
+	return mcp.WithToolAnnotation(mcp.ToolAnnotation{
+		ReadOnlyHint:    mcp.ToBoolPtr(true),
+		DestructiveHint: mcp.ToBoolPtr(false),
+		IdempotentHint:  mcp.ToBoolPtr(true),
+		OpenWorldHint:   mcp.ToBoolPtr(true),
+	})
+}
+
+// filterReadOnly drops tools not annotated as read-only when
+// ENBUILD_READ_ONLY is "true", so they are never listed or callable.
+func filterReadOnly(tools []server.ServerTool) []server.ServerTool {
+	if os.Getenv("ENBUILD_READ_ONLY") != "true" {
+		return tools
+	}
+	kept := tools[:0]
+	for _, tool := range tools {
+		if hint := tool.Tool.Annotations.ReadOnlyHint; hint == nil || !*hint {
+			infof("Read-only mode: not registering tool %s", tool.Tool.Name)
+			continue
+		}
+		kept = append(kept, tool)
+	}
+	return kept
+}
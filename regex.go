@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+const (
+	// maxPatternLength bounds the size of a search_catalogs_regex pattern.
+	maxPatternLength = 512
+	// regexMatchTimeout caps the time spent matching names against a pattern.
+	regexMatchTimeout = 5 * time.Second
+)
+
+// compileNamePattern compiles a user-supplied pattern. Go's RE2 engine
+// matches in linear time, so there is no catastrophic backtracking; the
+// length cap keeps compiled programs small.
+func compileNamePattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("pattern is required")
+	}
+	if len(pattern) > maxPatternLength {
+		return nil, fmt.Errorf("pattern must be at most %d bytes, got %d", maxPatternLength, len(pattern))
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("pattern does not compile: %v", err)
+	}
+	return re, nil
+}
+
+// filterByPattern returns the catalogs whose names match re, stopping with
+// an error if ctx is done first.
+func filterByPattern(ctx context.Context, catalogs []*enbuild.Catalog, re *regexp.Regexp) ([]*enbuild.Catalog, error) {
+	matched := []*enbuild.Catalog{}
+	for _, c := range catalogs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if re.MatchString(c.Name) {
+			matched = append(matched, c)
+		}
+	}
+	return matched, nil
+}
+
+func searchCatalogsRegex(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	pattern, _ := request.GetArguments()["pattern"].(string)
+	re, err := compileNamePattern(pattern)
+	if err != nil {
+		return formatValidationErrorResponse("Invalid pattern value", err)
+	}
+
+	opts, problem, err := getSearchParams(request)
+	if err != nil {
+		return formatValidationErrorResponse(problem, err)
+	}
+
+	creds, err := getCredentials(request)
+	if err != nil {
+		return formatCredentialsErrorResponse(err)
+	}
+
+	client, err := initializeClient(ctx, creds)
+	if err != nil {
+		return formatErrorResponse("Failed to initialize ENBUILD client", err)
+	}
+
+	catalogs, warning, err := listFilteredCatalogs(ctx, client, opts)
+	if err != nil {
+		return formatCallErrorResponse("Failed to list catalogs", err)
+	}
+
+	matchCtx, cancel := context.WithTimeout(ctx, regexMatchTimeout)
+	defer cancel()
+	matched, err := filterByPattern(matchCtx, catalogs, re)
+	if err != nil {
+		if ctx.Err() != nil {
+			return formatCallErrorResponse("Failed to match catalog names", ctx.Err())
+		}
+		return formatValidationErrorResponse(fmt.Sprintf("Matching catalog names took longer than %s; simplify the pattern or filter by type", regexMatchTimeout), err)
+	}
+	sortCatalogs(matched, "name", "asc")
+
+	response := CatalogResponse{
+		Success: true,
+		Count:   len(matched),
+		Data:    withWebURLs(creds.BaseURL, matched),
+		Message: fmt.Sprintf("%d of %d catalogs for VCS: %s match pattern %q", len(matched), len(catalogs), opts.VCS, pattern) + warning,
+	}
+
+	return formatJSONResponse(response)
+}
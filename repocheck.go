@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+// defaultRepoCheckTimeout bounds a repository reachability check when
+// ENBUILD_TIMEOUT is unset.
+const defaultRepoCheckTimeout = 10 * time.Second
+
+// repoContentKeys are the content keys that may hold a catalog's repository
+// URL, in order of preference. Keys are matched case-insensitively.
+var repoContentKeys = []string{"repository", "repository_url", "repo_url", "repourl", "repo", "git_url", "giturl", "source", "url"}
+
+// repoCheckTransport is the transport for reachability checks; nil uses
+// http.DefaultTransport. The self-test replaces it to stay offline.
+var repoCheckTransport http.RoundTripper
+
+// scpLikeRepo matches SSH remotes such as git@github.com:org/repo.git.
+var scpLikeRepo = regexp.MustCompile(`^[\w.-]+@([\w.-]+):(.+)$`)
+
+type repoCheck struct {
+	ID         interface{} `json:"id"`
+	VCS        string      `json:"vcs,omitempty"`
+	RepoURL    string      `json:"repo_url"`
+	Reachable  bool        `json:"reachable"`
+	StatusCode int         `json:"status_code,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// catalogRepoURL returns the catalog's repository as an http(s) URL, or ""
+// when its content names none. SSH remotes are rewritten to https.
+func catalogRepoURL(catalog *enbuild.Catalog) string {
+	for _, want := range repoContentKeys {
+		for key, value := range catalog.Content {
+			if !strings.EqualFold(key, want) {
+				continue
+			}
+			// package.json style: {"type": "git", "url": "..."}
+			if m, ok := value.(map[string]interface{}); ok {
+				value = m["url"]
+			}
+			if raw, ok := value.(string); ok {
+				if repo := normalizeRepoURL(raw); repo != "" {
+					return repo
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func normalizeRepoURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	raw = strings.TrimPrefix(raw, "git+")
+	if m := scpLikeRepo.FindStringSubmatch(raw); m != nil {
+		raw = "https://" + m[1] + "/" + m[2]
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	switch u.Scheme {
+	case "http", "https":
+	case "ssh", "git":
+		u.Scheme = "https"
+		u.Host = u.Hostname()
+	default:
+		return ""
+	}
+	// Credentials embedded in the URL are never sent or echoed back.
+	u.User = nil
+	return u.String()
+}
+
+// probeRepo sends a HEAD request to repo, retrying with GET for servers
+// that do not allow HEAD. Any status below 400 counts as reachable; private
+// repositories usually answer 404 to unauthenticated requests.
+func probeRepo(ctx context.Context, repo string) (int, error) {
+	timeout := defaultRepoCheckTimeout
+	if d, err := time.ParseDuration(os.Getenv("ENBUILD_TIMEOUT")); err == nil && d > 0 {
+		timeout = d
+	}
+	client := &http.Client{Timeout: timeout, Transport: repoCheckTransport}
+
+	status := 0
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := http.NewRequestWithContext(ctx, method, repo, nil)
+		if err != nil {
+			return 0, err
+		}
+		req.Header.Set("User-Agent", "mcp-server-enbuild")
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		status = resp.StatusCode
+		if status != http.StatusMethodNotAllowed && status != http.StatusNotImplemented {
+			break
+		}
+	}
+	return status, nil
+}
+
+func checkCatalogRepo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, ok := request.GetArguments()["id"].(string)
+	if !ok || id == "" {
+		return formatValidationErrorResponse("Missing required parameter", fmt.Errorf("catalog ID is required"))
+	}
+
+	creds, err := getCredentials(request)
+	if err != nil {
+		return formatCredentialsErrorResponse(err)
+	}
+
+	client, err := initializeClient(ctx, creds)
+	if err != nil {
+		return formatErrorResponse("Failed to initialize ENBUILD client", err)
+	}
+
+	catalog, err := getCatalogContext(ctx, client, id, &enbuild.CatalogListOptions{})
+	if err != nil {
+		return formatCallErrorResponse("Failed to get catalog", err)
+	}
+
+	repo := catalogRepoURL(catalog)
+	if repo == "" {
+		return formatTypedErrorResponse(errorTypeNotFound, "", "Cannot check repository", fmt.Errorf("catalog %s has no http(s) or SSH repository URL in its content", id))
+	}
+
+	check := repoCheck{ID: catalog.ID, VCS: catalog.VCS, RepoURL: repo}
+	status, err := probeRepo(ctx, repo)
+	if err != nil {
+		check.Error = err.Error()
+	} else {
+		check.StatusCode = status
+		check.Reachable = status < 400
+	}
+
+	message := fmt.Sprintf("Repository %s for catalog %s is reachable (HTTP %d)", repo, id, status)
+	switch {
+	case err != nil:
+		message = fmt.Sprintf("Repository %s for catalog %s is unreachable: %v", repo, id, err)
+	case status == http.StatusUnauthorized || status == http.StatusForbidden || status == http.StatusNotFound:
+		message = fmt.Sprintf("Repository %s for catalog %s is unreachable (HTTP %d); it may be missing or private", repo, id, status)
+	case !check.Reachable:
+		message = fmt.Sprintf("Repository %s for catalog %s is unreachable (HTTP %d)", repo, id, status)
+	}
+
+	response := CatalogResponse{
+		Success: true,
+		Count:   1,
+		Data:    check,
+		Message: message,
+	}
+
+	return formatJSONResponse(response)
+}
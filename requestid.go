@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// requestIDHeader carries a caller-chosen request ID on SSE and streamable
+// HTTP requests.
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds incoming IDs, which are written to the log.
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// requestIDFromHeader is the SSE and HTTP context function that makes an
+// incoming X-Request-ID available to requestIDMiddleware.
+func requestIDFromHeader(ctx context.Context, r *http.Request) context.Context {
+	if id := r.Header.Get(requestIDHeader); validRequestID(id) {
+		return context.WithValue(ctx, requestIDKey{}, id)
+	}
+	return ctx
+}
+
+// validRequestID accepts short IDs of printable ASCII without spaces, so a
+// caller-supplied ID cannot forge log lines.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r <= ' ' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// requestIDMiddleware tags every tool call with a request ID, taken from
+// the X-Request-ID header when the transport provides one and generated
+// otherwise. It logs the tool name, ID, and duration, and adds the ID to
+// the response as request_id. It runs inside outputFormatMiddleware, which
+// expects JSON.
+func requestIDMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, _ := ctx.Value(requestIDKey{}).(string)
+		if id == "" {
+			id = newRequestID()
+			ctx = context.WithValue(ctx, requestIDKey{}, id)
+		}
+
+		start := time.Now()
+		result, err := next(ctx, request)
+		infof("Tool %s request_id=%s duration=%s", request.Params.Name, id, time.Since(start).Round(time.Microsecond))
+		if err != nil || result == nil || len(result.Content) != 1 {
+			return result, err
+		}
+		text, ok := result.Content[0].(mcp.TextContent)
+		if !ok {
+			return result, nil
+		}
+
+		var response CatalogResponse
+		decoder := json.NewDecoder(strings.NewReader(text.Text))
+		decoder.UseNumber()
+		if err := decoder.Decode(&response); err != nil {
+			return result, nil
+		}
+		response.RequestID = id
+
+		tagged, err := formatJSONResponse(response)
+		if err != nil {
+			return nil, err
+		}
+		tagged.IsError = result.IsError
+		return tagged, nil
+	}
+}
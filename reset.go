@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// hasCredentialArguments reports whether the call overrides any of the
+// configured credentials.
+func hasCredentialArguments(request mcp.CallToolRequest) bool {
+	for _, name := range []string{"username", "password", "token", "client_id", "client_secret", "base_url", "backend"} {
+		if v, _ := request.GetArguments()[name].(string); v != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// resetClient discards cached ENBUILD clients so the next call logs in
+// again, e.g. after a password is rotated. With credential arguments only
+// the client for those credentials is dropped.
+func resetClient(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if hasCredentialArguments(request) {
+		creds, err := getCredentials(request)
+		if err != nil {
+			return formatCredentialsErrorResponse(err)
+		}
+		evictClient(credentialsKey(creds))
+		removeCachedToken(diskTokenCacheKey(creds))
+		return formatJSONResponse(CatalogResponse{
+			Success: true,
+			Message: fmt.Sprintf("Cleared the cached client for %s; the next call will authenticate again", creds.BaseURL),
+		})
+	}
+
+	n := resetClients()
+	if creds, err := getCredentials(mcp.CallToolRequest{}); err == nil {
+		removeCachedToken(diskTokenCacheKey(creds))
+	}
+	return formatJSONResponse(CatalogResponse{
+		Success: true,
+		Count:   n,
+		Message: fmt.Sprintf("Cleared %d cached clients; the next call will authenticate again", n),
+	})
+}
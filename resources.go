@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+const (
+	catalogResourceTemplate     = "enbuild://catalog/{id}"
+	catalogListResourceTemplate = "enbuild://catalogs/{vcs}"
+)
+
+// registerResources exposes catalogs as MCP resources. Resource reads carry
+// no arguments, so they always use the server's configured credentials.
+func registerResources(s *server.MCPServer) {
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate(catalogResourceTemplate, "ENBUILD catalog",
+			mcp.WithTemplateDescription("A single catalog by ID, as JSON"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		readCatalogResource,
+	)
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate(catalogListResourceTemplate, "ENBUILD catalogs by VCS",
+			mcp.WithTemplateDescription("Every catalog in a VCS ("+vcsChoices()+"), as JSON, each with its enbuild://catalog/{id} URI"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		readCatalogListResource,
+	)
+}
+
+// resourceArgument returns a variable matched from the resource URI.
+// mcp-go stores template matches as string slices.
+func resourceArgument(request mcp.ReadResourceRequest, name string) string {
+	switch v := request.Params.Arguments[name].(type) {
+	case []string:
+		if len(v) > 0 {
+			return v[0]
+		}
+	case string:
+		return v
+	}
+	return ""
+}
+
+func resourceClient(ctx context.Context) (CatalogService, string, error) {
+	creds, err := getCredentials(mcp.CallToolRequest{})
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid credentials: %v", err)
+	}
+	client, err := initializeClient(ctx, creds)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to initialize ENBUILD client: %v", err)
+	}
+	return client, creds.BaseURL, nil
+}
+
+func jsonResourceContents(uri string, v interface{}) ([]mcp.ResourceContents, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error formatting resource %s: %v", uri, err)
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      uri,
+		MIMEType: "application/json",
+		Text:     string(data),
+	}}, nil
+}
+
+func readCatalogResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	id := resourceArgument(request, "id")
+	if id == "" {
+		return nil, fmt.Errorf("catalog ID is required")
+	}
+
+	client, baseURL, err := resourceClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := getCatalogContext(ctx, client, id, &enbuild.CatalogListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get catalog %s: %v", id, err)
+	}
+
+	return jsonResourceContents(request.Params.URI, withWebURL(baseURL, catalog))
+}
+
+type catalogResourceEntry struct {
+	URI  string `json:"uri"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+}
+
+func readCatalogListResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	vcs := strings.ToUpper(resourceArgument(request, "vcs"))
+	if !isSupportedVCS(vcs) {
+		return nil, fmt.Errorf("VCS must be either %s", vcsChoices())
+	}
+
+	client, _, err := resourceClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	catalogs, err := listCatalogsContext(ctx, client, &enbuild.CatalogListOptions{VCS: vcs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list catalogs: %v", err)
+	}
+	sortCatalogs(catalogs, "name", "asc")
+
+	entries := make([]catalogResourceEntry, 0, len(catalogs))
+	for _, c := range catalogs {
+		id := fmt.Sprint(c.ID)
+		entries = append(entries, catalogResourceEntry{
+			URI:  strings.Replace(catalogResourceTemplate, "{id}", id, 1),
+			ID:   id,
+			Name: c.Name,
+			Type: c.Type,
+		})
+	}
+
+	return jsonResourceContents(request.Params.URI, entries)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	// defaultMaxRetryWait caps how long a throttled request waits before its
+	// retry when ENBUILD_MAX_RETRY_WAIT is unset.
+	defaultMaxRetryWait = 10 * time.Second
+	// defaultRetryAfter is the wait for a 429 without a usable Retry-After.
+	defaultRetryAfter = time.Second
+)
+
+// maxRetryWait returns ENBUILD_MAX_RETRY_WAIT; 0 disables retries.
+func maxRetryWait() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("ENBUILD_MAX_RETRY_WAIT")); err == nil && d >= 0 {
+		return d
+	}
+	return defaultMaxRetryWait
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP
+// date. It returns false when the header is missing or invalid.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d.Round(time.Second), true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// retryDelay randomizes the wait before a retry so that clients throttled
+// at the same moment do not all retry at the same moment. Without a
+// Retry-After the delay is drawn from [0, wait) ("full jitter"); with one,
+// up to half of it is added, so no retry comes earlier than the server
+// asked. random returns values in [0, 1); nil uses math/rand/v2, and tests
+// can pass a fixed source.
+func retryDelay(wait time.Duration, hinted bool, random func() float64) time.Duration {
+	if random == nil {
+		random = rand.Float64
+	}
+	if !hinted {
+		return time.Duration(random() * float64(wait))
+	}
+	return wait + time.Duration(random()*float64(wait/2))
+}
+
+// retryRateLimited sends req and, when the server answers 429 Too Many
+// Requests, waits for its Retry-After (see retryDelay) and sends it once
+// more. The retry is skipped when the wait would exceed maxRetryWait or the
+// time left before the request's deadline, or the request body cannot be
+// replayed. A response that is still 429 has the suggested wait appended
+// to its status, e.g. "429 Too Many Requests (retry after 30s)", because
+// the SDK only reports the status text; retryAfterFromError reads it back.
+func retryRateLimited(base http.RoundTripper, req *http.Request, random func() float64) (*http.Response, error) {
+	resp, err := base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return resp, err
+	}
+
+	wait, hinted := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	if !hinted {
+		wait = defaultRetryAfter
+	}
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	limit := maxRetryWait()
+	delay := min(retryDelay(wait, hinted, random), limit)
+	// Waiting past the deadline would only turn the 429 into a deadline
+	// error.
+	deadline, hasDeadline := req.Context().Deadline()
+	inTime := !hasDeadline || time.Until(deadline) >= delay
+	if limit > 0 && wait <= limit && replayable && inTime {
+		retry := req.Clone(req.Context())
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			retry.Body = body
+		}
+		resp.Body.Close()
+		warnf("%s %s was rate limited; retrying in %s", req.Method, req.URL.Redacted(), delay.Round(time.Millisecond))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+
+		resp, err = base.RoundTrip(retry)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+		if again, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			wait = again
+		}
+	}
+
+	resp.Status = fmt.Sprintf("%s (retry after %s)", resp.Status, wait)
+	return resp, nil
+}
+
+// retryAfterStatus matches the wait retryRateLimited appends to a status.
+var retryAfterStatus = regexp.MustCompile(`\(retry after ([0-9a-zµ.]+)\)`)
+
+// retryAfterFromError returns the wait suggested by a throttled ENBUILD
+// call, in whole seconds, or 0 if there is none.
+func retryAfterFromError(err error) int {
+	if err == nil {
+		return 0
+	}
+	m := retryAfterStatus.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 0
+	}
+	d, parseErr := time.ParseDuration(m[1])
+	if parseErr != nil {
+		return 0
+	}
+	seconds := int((d + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryDelayWithFixedSource(t *testing.T) {
+	half := func() float64 { return 0.5 }
+	for _, tc := range []struct {
+		wait   time.Duration
+		hinted bool
+		want   time.Duration
+	}{
+		{wait: 10 * time.Second, hinted: false, want: 5 * time.Second},
+		{wait: 10 * time.Second, hinted: true, want: 12500 * time.Millisecond},
+		{wait: 0, hinted: true, want: 0},
+	} {
+		if got := retryDelay(tc.wait, tc.hinted, half); got != tc.want {
+			t.Errorf("retryDelay(%s, %t) = %s, want %s", tc.wait, tc.hinted, got, tc.want)
+		}
+	}
+	if got := retryDelay(10*time.Second, true, func() float64 { return 0 }); got != 10*time.Second {
+		t.Errorf("hinted retry came after %s, want no earlier than the 10s Retry-After", got)
+	}
+}
+
+func TestEnbuildTransportRetriesWithItsRandomSource(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	draws := 0
+	transport := newEnbuildTransport(&http.Transport{}, "", nil, func() float64 {
+		draws++
+		return 0
+	})
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || requests != 2 {
+		t.Errorf("got status %d after %d requests, want 200 after 2", resp.StatusCode, requests)
+	}
+	if draws != 1 {
+		t.Errorf("random source drawn %d times, want 1", draws)
+	}
+}
+
+func TestRetryRateLimitedReturns429WhenDeadlineIsTooClose(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	resp, err := retryRateLimited(&http.Transport{}, req, nil)
+	if err != nil {
+		t.Fatalf("got %v, want the 429 response", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusTooManyRequests || requests != 1 {
+		t.Errorf("got status %d after %d requests, want 429 after 1", resp.StatusCode, requests)
+	}
+	if elapsed := time.Since(start); elapsed >= 100*time.Millisecond {
+		t.Errorf("waited %s for a retry that could not finish before the deadline", elapsed)
+	}
+	if !strings.Contains(resp.Status, "(retry after 5s)") {
+		t.Errorf("status %q does not carry the Retry-After", resp.Status)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// resolveSecretRef fetches username and password from a secret manager.
+// Supported references:
+//
+//	aws-sm://<secret-id>          AWS Secrets Manager, via the aws CLI
+//	gcp-sm://<project>/<secret>   GCP Secret Manager, via the gcloud CLI
+//	vault://<kv-v2 path>          HashiCorp Vault, using VAULT_ADDR and VAULT_TOKEN
+//
+// The secret must be a JSON object with "username" and "password" keys.
+// Provider tooling is only invoked when a reference is given.
+func resolveSecretRef(ref string) (string, string, error) {
+	scheme, path, ok := strings.Cut(ref, "://")
+	if !ok || path == "" {
+		return "", "", fmt.Errorf("invalid secret reference %q: expected <scheme>://<path>", ref)
+	}
+
+	var payload []byte
+	var err error
+	switch scheme {
+	case "aws-sm":
+		payload, err = exec.Command("aws", "secretsmanager", "get-secret-value",
+			"--secret-id", path, "--query", "SecretString", "--output", "text").Output()
+	case "gcp-sm":
+		project, secret, found := strings.Cut(path, "/")
+		if !found || project == "" || secret == "" {
+			return "", "", fmt.Errorf("invalid secret reference %q: expected gcp-sm://<project>/<secret>", ref)
+		}
+		payload, err = exec.Command("gcloud", "secrets", "versions", "access", "latest",
+			"--project", project, "--secret", secret).Output()
+	case "vault":
+		payload, err = readVaultSecret(path)
+	default:
+		return "", "", fmt.Errorf("unsupported secret reference scheme %q (use aws-sm, gcp-sm, or vault)", scheme)
+	}
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			err = fmt.Errorf("%v: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", "", fmt.Errorf("failed to fetch secret %q: %v", ref, err)
+	}
+
+	var creds struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if err := json.Unmarshal(payload, &creds); err != nil {
+		return "", "", fmt.Errorf("secret %q is not a JSON object: %v", ref, err)
+	}
+	if creds.Username == "" || creds.Password == "" {
+		return "", "", fmt.Errorf("secret %q must contain non-empty \"username\" and \"password\" keys", ref)
+	}
+	return creds.Username, creds.Password, nil
+}
+
+func readVaultSecret(path string) ([]byte, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	token := os.Getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		return nil, fmt.Errorf("VAULT_ADDR and VAULT_TOKEN must be set")
+	}
+
+	// KV v2 paths are addressed as <mount>/data/<path>.
+	mount, rest, ok := strings.Cut(path, "/")
+	if !ok || rest == "" {
+		return nil, fmt.Errorf("expected vault://<mount>/<path>")
+	}
+	endpoint, err := url.JoinPath(addr, "v1", mount, "data", rest)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+
+	resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault returned %s", resp.Status)
+	}
+
+	var body struct {
+		Data struct {
+			Data json.RawMessage `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return body.Data.Data, nil
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+// selfTestArguments holds representative arguments for every registered
+// tool. A tool without an entry fails the self-test, so new tools must be
+// added here.
+var selfTestArguments = map[string]map[string]interface{}{
+	"catalog_freshness":     {"vcs": "GITHUB"},
+	"catalog_stats":         {},
+	"check_catalog_repo":    {"id": "1"},
+	"compare_catalogs":      {"id_a": "1", "id_b": "2"},
+	"count_catalogs":        {"vcs": "GITLAB", "type": "ansible"},
+	"debug_config":          {},
+	"estimate_search_cost":  {"vcs": "GITHUB", "name": "vpc"},
+	"get_catalog_details":   {"id": "1"},
+	"get_catalog_inputs":    {"id": "1"},
+	"get_catalog_readme":    {"id": "1"},
+	"get_catalogs":          {"ids": []interface{}{"1", "2"}},
+	"list_catalog_types":    {},
+	"list_catalogs_by_vcs":  {},
+	"list_vcs":              {},
+	"ping":                  {},
+	"server_info":           {},
+	"search_catalogs_regex": {"pattern": "^aws-", "vcs": "GITHUB"},
+	"reset_client":          {},
+	"search_catalogs":       {"name": "vpc", "type": "terraform", "vcs": "GITHUB", "limit": 1},
+	"list_writable_catalogs": {
+		"vcs": "GITHUB",
+	},
+	"get_catalog_deployments": {
+		"id":    "1",
+		"limit": 5,
+	},
+	"validate_catalog_inputs": {
+		"id":     "1",
+		"inputs": map[string]interface{}{"region": "us-east-1"},
+	},
+}
+
+// selfTestTransport answers every repository check with 200 OK, so the
+// self-test never leaves the machine.
+type selfTestTransport struct{}
+
+func (selfTestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+}
+
+type mockCatalogService struct {
+	catalogs []*enbuild.Catalog
+}
+
+func newMockCatalogService() *mockCatalogService {
+	return &mockCatalogService{catalogs: []*enbuild.Catalog{
+		{
+			ID:          "1",
+			Name:        "aws-vpc",
+			Description: "AWS VPC module",
+			Type:        "terraform",
+			VCS:         "GITHUB",
+			Slug:        "aws-vpc",
+			Version:     "1.2.0",
+			CreatedOn:   "2024-01-10T12:00:00Z",
+			UpdatedOn:   "2024-06-01T12:00:00Z",
+			Content: map[string]interface{}{
+				"repository": "https://github.com/example/terraform-aws-vpc",
+				"inputs": []interface{}{
+					map[string]interface{}{"name": "region", "type": "string", "required": true},
+					map[string]interface{}{"name": "cidr", "type": "string", "default": "10.0.0.0/16"},
+				},
+			},
+		},
+		{
+			ID:      "2",
+			Name:    "aws-vpc-endpoints",
+			Type:    "terraform",
+			VCS:     "GITHUB",
+			Slug:    "aws-vpc-endpoints",
+			Version: "0.3.0",
+		},
+		{
+			ID:      "3",
+			Name:    "k8s-baseline",
+			Type:    "ansible",
+			VCS:     "GITLAB",
+			Slug:    "k8s-baseline",
+			Version: "2.0.0",
+		},
+	}}
+}
+
+func (m *mockCatalogService) List(opts ...*enbuild.CatalogListOptions) ([]*enbuild.Catalog, error) {
+	var filtered []*enbuild.Catalog
+	for _, c := range m.catalogs {
+		if len(opts) > 0 && opts[0] != nil && !catalogMatches(c, opts[0]) {
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+	return filtered, nil
+}
+
+func (m *mockCatalogService) Get(id string, opts *enbuild.CatalogListOptions) (*enbuild.Catalog, error) {
+	for _, c := range m.catalogs {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("API error: 404 Not Found")
+}
+
+// runSelfTest registers all tools against an in-memory catalog service,
+// calls each one with representative arguments, and prints a pass/fail
+// summary. It returns the process exit code.
+func runSelfTest() int {
+	mock := newMockCatalogService()
+	newCatalogService = func(creds credentials) (CatalogService, error) {
+		return mock, nil
+	}
+	repoCheckTransport = selfTestTransport{}
+	os.Unsetenv("ENBUILD_TOKEN_CACHE")
+	os.Setenv("ENBUILD_BASE_URL", "https://enbuild.example.com")
+	os.Setenv("ENBUILD_USERNAME", "self-test")
+	os.Setenv("ENBUILD_PASSWORD", "self-test")
+
+	s, err := newServer()
+	if err != nil {
+		fmt.Printf("FAIL  startup: %v\n", err)
+		return 1
+	}
+	ctx := context.Background()
+
+	tools, err := selfTestListTools(ctx, s)
+	if err != nil {
+		fmt.Printf("FAIL  tools/list: %v\n", err)
+		return 1
+	}
+	sort.Strings(tools)
+
+	failed := 0
+	for _, name := range tools {
+		if err := selfTestCallTool(ctx, s, name); err != nil {
+			failed++
+			fmt.Printf("FAIL  %s: %v\n", name, err)
+			continue
+		}
+		fmt.Printf("PASS  %s\n", name)
+	}
+
+	fmt.Printf("\n%d tools, %d passed, %d failed\n", len(tools), len(tools)-failed, failed)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func selfTestRequest(ctx context.Context, s *server.MCPServer, id int, method string, params interface{}) (json.RawMessage, error) {
+	message, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      id,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(s.HandleMessage(ctx, message))
+	if err != nil {
+		return nil, err
+	}
+
+	var reply struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(raw, &reply); err != nil {
+		return nil, err
+	}
+	if reply.Error != nil {
+		return nil, fmt.Errorf("JSON-RPC error: %s", reply.Error.Message)
+	}
+	return reply.Result, nil
+}
+
+func selfTestListTools(ctx context.Context, s *server.MCPServer) ([]string, error) {
+	result, err := selfTestRequest(ctx, s, 1, string(mcp.MethodToolsList), map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	var list mcp.ListToolsResult
+	if err := json.Unmarshal(result, &list); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(list.Tools))
+	for _, tool := range list.Tools {
+		names = append(names, tool.Name)
+	}
+	return names, nil
+}
+
+func selfTestCallTool(ctx context.Context, s *server.MCPServer, name string) error {
+	args, ok := selfTestArguments[name]
+	if !ok {
+		return fmt.Errorf("no self-test arguments defined")
+	}
+
+	result, err := selfTestRequest(ctx, s, 2, string(mcp.MethodToolsCall), map[string]interface{}{
+		"name":      name,
+		"arguments": args,
+	})
+	if err != nil {
+		return err
+	}
+
+	var call struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(result, &call); err != nil {
+		return err
+	}
+	if len(call.Content) != 1 || call.Content[0].Type != "text" {
+		return fmt.Errorf("expected a single text content item")
+	}
+
+	var response CatalogResponse
+	if err := json.Unmarshal([]byte(call.Content[0].Text), &response); err != nil {
+		return fmt.Errorf("response is not a valid CatalogResponse: %v", err)
+	}
+	if !response.Success {
+		return fmt.Errorf("tool reported failure: %s", response.Message)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type serverInfo struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+}
+
+func getServerInfo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	response := CatalogResponse{
+		Success: true,
+		Data: serverInfo{
+			Name:        serverName,
+			Version:     serverVersion,
+			Description: serverDescription,
+		},
+		Message: "ENBUILD MCP server " + serverVersion,
+	}
+
+	return formatJSONResponse(response)
+}
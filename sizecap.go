@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func responseByteLimit() int {
+	n, _ := strconv.Atoi(os.Getenv("ENBUILD_MAX_RESPONSE_BYTES"))
+	return n
+}
+
+func responseSize(response CatalogResponse) int {
+	data, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+// capResponseSize drops items from the end of the response's data array
+// until the marshaled response fits in limit bytes. It returns how many
+// items were dropped.
+func capResponseSize(response *CatalogResponse, limit int) int {
+	items, ok := response.Data.([]interface{})
+	if !ok || len(items) == 0 || responseSize(*response) <= limit {
+		return 0
+	}
+
+	trimmed := *response
+	trimmed.Truncated = true
+	fits := func(n int) bool {
+		trimmed.Data = items[:n]
+		trimmed.Message = response.Message + sizeCapNote(len(items)-n, n, limit)
+		return responseSize(trimmed) <= limit
+	}
+
+	// Find the largest prefix that fits; an empty array always stays.
+	lo, hi := 0, len(items)-1
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if fits(mid) {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+
+	omitted := len(items) - lo
+	response.Data = items[:lo]
+	response.Message += sizeCapNote(omitted, lo, limit)
+	response.Truncated = true
+	if response.Count == len(items) {
+		response.Count = lo
+	}
+	return omitted
+}
+
+// pageStateKey carries a *pageState from responseSizeMiddleware to
+// search_catalogs, which records where its page starts so that a capped page
+// can be given a resume token for its first omitted item.
+type pageStateKey struct{}
+
+type pageState struct {
+	key []byte
+	pos resumePosition
+}
+
+// recordPage stores the resume position of the page's first item for
+// responseSizeMiddleware; it does nothing outside the middleware.
+func recordPage(ctx context.Context, key []byte, pos resumePosition) {
+	if state, ok := ctx.Value(pageStateKey{}).(*pageState); ok {
+		state.key, state.pos = key, pos
+	}
+}
+
+// resumeAfterCap points the response's resume token at the first item the
+// size cap dropped, replacing the handler's token, which would skip them.
+func resumeAfterCap(response *CatalogResponse, state *pageState) error {
+	items, _ := response.Data.([]interface{})
+	if state.key == nil || len(items) == 0 {
+		return nil
+	}
+	pos := state.pos
+	pos.Offset += len(items)
+	if pos.Limit == 0 {
+		pos.Limit = len(items)
+	}
+	token, err := encodeResumeToken(state.key, pos)
+	if err != nil {
+		return fmt.Errorf("error creating resume token: %v", err)
+	}
+	response.ResumeToken = token
+	response.Message += "; follow resume_token to continue from the first omitted catalog"
+	return nil
+}
+
+func sizeCapNote(omitted, kept, limit int) string {
+	if kept == 0 {
+		return fmt.Sprintf(" (%d items omitted because even one does not fit in %d bytes; use fields or max_field_length to shrink them)", omitted, limit)
+	}
+	return fmt.Sprintf(" (%d items omitted to keep the response under %d bytes; request at most %d at a time, or use fields or max_field_length, to get the rest)", omitted, limit, kept)
+}
+
+// responseSizeMiddleware applies --max-response-bytes to the final JSON of
+// every tool response, after fields and max_field_length have been applied.
+func responseSizeMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		state := &pageState{}
+		result, err := next(context.WithValue(ctx, pageStateKey{}, state), request)
+		limit := responseByteLimit()
+		if err != nil || result == nil || limit <= 0 || len(result.Content) != 1 {
+			return result, err
+		}
+		text, ok := result.Content[0].(mcp.TextContent)
+		if !ok || len(text.Text) <= limit {
+			return result, nil
+		}
+
+		var response CatalogResponse
+		decoder := json.NewDecoder(strings.NewReader(text.Text))
+		decoder.UseNumber()
+		if err := decoder.Decode(&response); err != nil {
+			return result, nil
+		}
+		if capResponseSize(&response, limit) == 0 {
+			return result, nil
+		}
+		if err := resumeAfterCap(&response, state); err != nil {
+			return nil, err
+		}
+
+		capped, err := formatJSONResponse(response)
+		if err != nil {
+			return nil, err
+		}
+		capped.IsError = result.IsError
+		return capped, nil
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestResponseSizeCapResumesAfterLastKeptItem(t *testing.T) {
+	key := []byte("test key")
+	page := resumePosition{Offset: 10, Limit: 5, VCS: "GITHUB"}
+	handler := responseSizeMiddleware(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		recordPage(ctx, key, page)
+		handlerToken, _ := encodeResumeToken(key, resumePosition{Offset: 15, Limit: 5, VCS: "GITHUB"})
+		items := make([]interface{}, 5)
+		for i := range items {
+			items[i] = map[string]interface{}{"description": strings.Repeat("d", 200)}
+		}
+		return formatJSONResponse(CatalogResponse{Success: true, Count: 5, Total: 30, Data: items, ResumeToken: handlerToken})
+	})
+	t.Setenv("ENBUILD_MAX_RESPONSE_BYTES", "900")
+
+	result, err := handler(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var response CatalogResponse
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response); err != nil {
+		t.Fatal(err)
+	}
+	kept := len(response.Data.([]interface{}))
+	if kept == 0 || kept == 5 {
+		t.Fatalf("kept %d of 5 items; the test needs a partial cap", kept)
+	}
+	pos, err := decodeResumeToken(key, response.ResumeToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pos.Offset != page.Offset+kept || pos.Limit != page.Limit {
+		t.Errorf("resume token at offset %d, limit %d; want offset %d, limit %d", pos.Offset, pos.Limit, page.Offset+kept, page.Limit)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+const (
+	defaultSortField = "name"
+	defaultSortOrder = "asc"
+)
+
+// getSortParams reads the optional sort and order arguments, defaulting to
+// name ascending.
+func getSortParams(request mcp.CallToolRequest) (string, string, error) {
+	field, _ := request.GetArguments()["sort"].(string)
+	order, _ := request.GetArguments()["order"].(string)
+	field = strings.ToLower(strings.TrimSpace(field))
+	order = strings.ToLower(strings.TrimSpace(order))
+	if field == "" {
+		field = defaultSortField
+	}
+	if order == "" {
+		order = defaultSortOrder
+	}
+
+	switch field {
+	case "name", "type", "created":
+	default:
+		return "", "", fmt.Errorf("sort must be one of name, type, or created")
+	}
+	if order != "asc" && order != "desc" {
+		return "", "", fmt.Errorf("order must be either asc or desc")
+	}
+	return field, order, nil
+}
+
+// sortCatalogs orders catalogs in place. The sort is stable, so catalogs
+// with equal keys keep their incoming order: the API's, or catalog ID order
+// with --deterministic-order, which makes the ID the tiebreaker here.
+// Catalogs without a parseable creation time sort last in either order.
+func sortCatalogs(catalogs []*enbuild.Catalog, field, order string) {
+	desc := order == "desc"
+	sort.SliceStable(catalogs, func(i, j int) bool {
+		a, b := catalogs[i], catalogs[j]
+		switch field {
+		case "created":
+			ta, okA := parseCatalogTime(a.CreatedOn)
+			tb, okB := parseCatalogTime(b.CreatedOn)
+			if !okA || !okB {
+				return okA && !okB
+			}
+			if desc {
+				return ta.After(tb)
+			}
+			return ta.Before(tb)
+		case "type":
+			return compareFold(a.Type, b.Type, desc)
+		default:
+			return compareFold(a.Name, b.Name, desc)
+		}
+	})
+}
+
+func compareFold(a, b string, desc bool) bool {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	if desc {
+		return a > b
+	}
+	return a < b
+}
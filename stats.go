@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+// unknownStatsKey groups catalogs with no type or VCS in catalog_stats.
+const unknownStatsKey = "unknown"
+
+type catalogStats struct {
+	Total  int            `json:"total"`
+	ByType map[string]int `json:"by_type"`
+	ByVCS  map[string]int `json:"by_vcs"`
+}
+
+func summarizeCatalogs(catalogs []*enbuild.Catalog) catalogStats {
+	stats := catalogStats{Total: len(catalogs), ByType: map[string]int{}, ByVCS: map[string]int{}}
+	for _, c := range catalogs {
+		catalogType := strings.TrimSpace(c.Type)
+		if catalogType == "" {
+			catalogType = unknownStatsKey
+		}
+		vcs := strings.ToUpper(strings.TrimSpace(c.VCS))
+		if vcs == "" {
+			vcs = unknownStatsKey
+		}
+		stats.ByType[catalogType]++
+		stats.ByVCS[vcs]++
+	}
+	return stats
+}
+
+func catalogStatsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	vcs, _ := request.GetArguments()["vcs"].(string)
+	vcs = strings.ToUpper(strings.TrimSpace(vcs))
+	if vcs == "" {
+		vcs = allVCS
+	}
+	if vcs != allVCS && !isSupportedVCS(vcs) {
+		return formatValidationErrorResponse("Invalid VCS value", fmt.Errorf("VCS must be %s", vcsChoices(allVCS)))
+	}
+
+	creds, err := getCredentials(request)
+	if err != nil {
+		return formatCredentialsErrorResponse(err)
+	}
+
+	client, err := initializeClient(ctx, creds)
+	if err != nil {
+		return formatErrorResponse("Failed to initialize ENBUILD client", err)
+	}
+
+	catalogs, warning, err := listFilteredCatalogs(ctx, client, &enbuild.CatalogListOptions{VCS: vcs})
+	if err != nil {
+		return formatCallErrorResponse("Failed to list catalogs", err)
+	}
+
+	stats := summarizeCatalogs(catalogs)
+	response := CatalogResponse{
+		Success: true,
+		Count:   stats.Total,
+		Data:    stats,
+		Message: fmt.Sprintf("%d catalogs across %d types and %d VCS for VCS: %s", stats.Total, len(stats.ByType), len(stats.ByVCS), vcs) + warning,
+	}
+
+	return formatJSONResponse(response)
+}
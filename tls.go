@@ -0,0 +1,25 @@
+package main
+
+import (
+	"crypto/tls"
+	"os"
+)
+
+// configureInsecureTLS disables TLS certificate verification for outbound
+// requests when ENBUILD_INSECURE_SKIP_VERIFY is "true". The SDK, Keycloak,
+// and token clients all use http.DefaultTransport, so it is changed in
+// place rather than passed to each client.
+func configureInsecureTLS() {
+	if os.Getenv("ENBUILD_INSECURE_SKIP_VERIFY") != "true" {
+		return
+	}
+	transport := defaultHTTPTransport()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.InsecureSkipVerify = true
+	warnf("************************************************************")
+	warnf("TLS certificate verification is DISABLED (--insecure or ENBUILD_INSECURE_SKIP_VERIFY).")
+	warnf("Connections to ENBUILD can be intercepted. Do not use this in production.")
+	warnf("************************************************************")
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+const (
+	apiVersionPath        = "/enbuild-bk/api/v1/"
+	defaultRequestTimeout = 30 * time.Second
+)
+
+// tokenCatalogService talks to the ENBUILD catalog API with a pre-issued
+// bearer token. The SDK only supports Keycloak username/password auth, so
+// this mirrors its List and Get calls (same endpoints, decoding, and
+// filtering) for token-based callers. token is called before every
+// request, so it may refresh an expiring token. Requests use ctx, set per
+// call by withContext, so a cancelled tool call aborts them.
+type tokenCatalogService struct {
+	baseURL    *url.URL
+	token      func(ctx context.Context) (string, error)
+	httpClient *http.Client
+	ctx        context.Context
+}
+
+func newTokenCatalogService(baseURL, token string) (*tokenCatalogService, error) {
+	if !strings.Contains(baseURL, apiVersionPath) {
+		baseURL = strings.TrimSuffix(baseURL, "/") + apiVersionPath
+	}
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %v", err)
+	}
+
+	timeout := defaultRequestTimeout
+	if d, err := time.ParseDuration(os.Getenv("ENBUILD_TIMEOUT")); err == nil && d > 0 {
+		timeout = d
+	}
+
+	return &tokenCatalogService{
+		baseURL:    parsed,
+		token:      func(context.Context) (string, error) { return token, nil },
+		httpClient: &http.Client{Timeout: timeout},
+		ctx:        context.Background(),
+	}, nil
+}
+
+// withContext returns a copy of s whose requests use ctx.
+func (s *tokenCatalogService) withContext(ctx context.Context) CatalogService {
+	bound := *s
+	bound.ctx = ctx
+	return &bound
+}
+
+func (s *tokenCatalogService) do(ctx context.Context, path string, v interface{}) error {
+	u, err := s.baseURL.Parse(path)
+	if err != nil {
+		return err
+	}
+	token, err := s.token(ctx)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "mcp-server-enbuild")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("API error: %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func (s *tokenCatalogService) fetch(path string) ([]*enbuild.Catalog, error) {
+	var resp struct {
+		Data []*enbuild.Catalog `json:"data"`
+	}
+	if err := s.do(s.ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	for _, catalog := range resp.Data {
+		if id, ok := catalog.ID.(float64); ok {
+			catalog.ID = fmt.Sprintf("%v", int64(id))
+		}
+	}
+	return resp.Data, nil
+}
+
+func (s *tokenCatalogService) List(opts ...*enbuild.CatalogListOptions) ([]*enbuild.Catalog, error) {
+	catalogs, err := s.fetch("manifests")
+	if err != nil {
+		return nil, err
+	}
+	if len(opts) == 0 || opts[0] == nil {
+		return catalogs, nil
+	}
+
+	var filtered []*enbuild.Catalog
+	for _, c := range catalogs {
+		if catalogMatches(c, opts[0]) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered, nil
+}
+
+func (s *tokenCatalogService) Get(id string, opts *enbuild.CatalogListOptions) (*enbuild.Catalog, error) {
+	if id == "" {
+		return nil, fmt.Errorf("catalog ID is required")
+	}
+	catalogs, err := s.fetch("manifests/" + url.PathEscape(id))
+	if err != nil {
+		return nil, err
+	}
+	if len(catalogs) == 0 {
+		return nil, fmt.Errorf("API error: catalog %s not found", id)
+	}
+	return catalogs[0], nil
+}
+
+// catalogMatches applies the same client-side filters as the SDK's List.
+func catalogMatches(c *enbuild.Catalog, opts *enbuild.CatalogListOptions) bool {
+	if opts.ID != "" && !strings.EqualFold(fmt.Sprint(c.ID), opts.ID) {
+		return false
+	}
+	if opts.VCS != "" && !strings.EqualFold(c.VCS, opts.VCS) {
+		return false
+	}
+	if opts.Type != "" && !strings.EqualFold(c.Type, opts.Type) {
+		return false
+	}
+	if opts.Slug != "" && !strings.EqualFold(c.Slug, opts.Slug) {
+		return false
+	}
+	if opts.Name != "" && !strings.Contains(strings.ToLower(c.Name), strings.ToLower(opts.Name)) {
+		return false
+	}
+	if opts.Description != "" && !strings.Contains(strings.ToLower(c.Description), strings.ToLower(opts.Description)) {
+		return false
+	}
+	if opts.Version != "" && !strings.EqualFold(c.Version, opts.Version) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// tokenCacheFile is the on-disk token cache, relative to the user config
+// directory ($XDG_CONFIG_HOME or ~/.config on Linux).
+const tokenCacheFile = "enbuild-mcp-server/tokens.json"
+
+// tokenCacheSecretFile holds the random key that cache keys fingerprint the
+// password or client secret with, next to the cache file.
+const tokenCacheSecretFile = "enbuild-mcp-server/tokens.key"
+
+const tokenCacheSecretSize = 32
+
+// tokenCacheMu serializes reads and writes of the cache file within this
+// process. Concurrent processes may overwrite each other's entries, which
+// only costs an extra login.
+var tokenCacheMu sync.Mutex
+
+type cachedToken struct {
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+func tokenCachePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, tokenCacheFile), nil
+}
+
+// diskTokenCacheKey returns the cache key for the token of creds, or "" when
+// it must not be cached on disk: unless --token-cache is set, or when creds
+// are not the server's startup credentials. The key includes an HMAC of the
+// password or client secret under tokenCacheSecret, so a changed password
+// never picks up the old password's token, while the cache file alone holds
+// nothing that a guessed password can be checked against.
+func diskTokenCacheKey(creds credentials) string {
+	if os.Getenv("ENBUILD_TOKEN_CACHE") != "true" || creds.Token != "" {
+		return ""
+	}
+	if startup, err := getCredentials(mcp.CallToolRequest{}); err != nil || startup != creds {
+		return ""
+	}
+	secret, err := tokenCacheSecret()
+	if err != nil {
+		warnf("Not caching the Keycloak token on disk: %v", err)
+		return ""
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(creds.Password + "\x00" + creds.ClientSecret))
+	sum := sha256.Sum256([]byte(creds.BaseURL + "\x00" + creds.Username + "\x00" + creds.ClientID + "\x00" + string(mac.Sum(nil))))
+	return hex.EncodeToString(sum[:])
+}
+
+// tokenCacheSecret returns the key for password fingerprints. Replacing the
+// key only invalidates the cached tokens.
+func tokenCacheSecret() ([]byte, error) {
+	return localSecret(tokenCacheSecretFile)
+}
+
+// localSecretMu serializes creating the files behind localSecret.
+var localSecretMu sync.Mutex
+
+// localSecret returns the random key stored in file, relative to the user
+// config directory, creating it with 0600 permissions on first use.
+func localSecret(file string) ([]byte, error) {
+	localSecretMu.Lock()
+	defer localSecretMu.Unlock()
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, file)
+	secret, err := os.ReadFile(path)
+	if err == nil && len(secret) == tokenCacheSecretSize {
+		return secret, nil
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	secret = make([]byte, tokenCacheSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, secret, 0o600); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+func readTokenCache(path string) (map[string]cachedToken, error) {
+	tokens := map[string]cachedToken{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("invalid token cache %s: %v", path, err)
+	}
+	return tokens, nil
+}
+
+// writeTokenCache replaces the cache file atomically, dropping expired
+// tokens. The file is created 0600 because it holds bearer tokens.
+func writeTokenCache(path string, tokens map[string]cachedToken) error {
+	now := time.Now()
+	for key, token := range tokens {
+		if !now.Before(token.ExpiresAt) {
+			delete(tokens, key)
+		}
+	}
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tokens-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// loadCachedToken returns the unexpired token stored under key.
+func loadCachedToken(key string) (string, time.Time, bool) {
+	tokenCacheMu.Lock()
+	defer tokenCacheMu.Unlock()
+
+	path, err := tokenCachePath()
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	tokens, err := readTokenCache(path)
+	if err != nil {
+		warnf("Ignoring the token cache: %v", err)
+		return "", time.Time{}, false
+	}
+	token, ok := tokens[key]
+	if !ok || token.AccessToken == "" || !time.Now().Before(token.ExpiresAt) {
+		return "", time.Time{}, false
+	}
+	return token.AccessToken, token.ExpiresAt, true
+}
+
+func saveCachedToken(key, accessToken string, expiresAt time.Time) error {
+	return updateTokenCache(func(tokens map[string]cachedToken) bool {
+		tokens[key] = cachedToken{AccessToken: accessToken, ExpiresAt: expiresAt}
+		return true
+	})
+}
+
+// removeCachedToken forgets the token stored under key, e.g. after ENBUILD
+// rejected it, so the next login does not pick it up again.
+func removeCachedToken(key string) {
+	if key == "" {
+		return
+	}
+	err := updateTokenCache(func(tokens map[string]cachedToken) bool {
+		_, ok := tokens[key]
+		delete(tokens, key)
+		return ok
+	})
+	if err != nil {
+		warnf("Could not update the token cache: %v", err)
+	}
+}
+
+// updateTokenCache applies update to the cached tokens and writes them back
+// if update reports a change.
+func updateTokenCache(update func(map[string]cachedToken) bool) error {
+	tokenCacheMu.Lock()
+	defer tokenCacheMu.Unlock()
+
+	path, err := tokenCachePath()
+	if err != nil {
+		return err
+	}
+	tokens, err := readTokenCache(path)
+	if err != nil {
+		// A corrupt cache is replaced rather than blocking logins.
+		tokens = map[string]cachedToken{}
+	}
+	if !update(tokens) {
+		return nil
+	}
+	return writeTokenCache(path, tokens)
+}
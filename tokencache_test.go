@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestDiskTokenCacheKeyChangesWithPassword(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("ENBUILD_TOKEN_CACHE", "true")
+	t.Setenv("ENBUILD_BASE_URL", "https://enbuild.example.com")
+	t.Setenv("ENBUILD_USERNAME", "alice")
+
+	key := func(password string) string {
+		t.Setenv("ENBUILD_PASSWORD", password)
+		creds, err := getCredentials(mcp.CallToolRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return diskTokenCacheKey(creds)
+	}
+
+	first := key("old-password")
+	if first == "" {
+		t.Fatal("startup credentials were not cached with ENBUILD_TOKEN_CACHE=true")
+	}
+	if again := key("old-password"); again != first {
+		t.Errorf("key is not stable for the same password: %s, then %s", first, again)
+	}
+	if changed := key("new-password"); changed == first {
+		t.Error("key did not change with the password")
+	}
+}
+
+func TestDiskTokenCacheKeyOffByDefault(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("ENBUILD_TOKEN_CACHE", "")
+	t.Setenv("ENBUILD_BASE_URL", "https://enbuild.example.com")
+	t.Setenv("ENBUILD_USERNAME", "alice")
+	t.Setenv("ENBUILD_PASSWORD", "secret")
+
+	creds, err := getCredentials(mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key := diskTokenCacheKey(creds); key != "" {
+		t.Errorf("got cache key %s without --token-cache", key)
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Spans are exported to an OpenTelemetry collector with the OpenTelemetry
+// SDK's OTLP/HTTP exporter, which sends protobuf to /v1/traces and reads
+// OTEL_EXPORTER_OTLP_HEADERS and the other standard variables itself.
+const (
+	defaultTraceServiceName = "enbuild-mcp-server"
+	traceExportInterval     = 5 * time.Second
+	traceExportTimeout      = 10 * time.Second
+	traceBatchSize          = 512
+	// traceQueueLimit caps spans held while the collector is unreachable;
+	// newer spans are dropped beyond it.
+	traceQueueLimit = 4096
+)
+
+const (
+	spanKindServer = trace.SpanKindServer
+	spanKindClient = trace.SpanKindClient
+)
+
+// tracedArguments are the tool arguments recorded on tool spans. Credentials
+// and free-form inputs are never recorded.
+var tracedArguments = []string{"id", "ids", "vcs", "name", "type", "pattern", "match", "sort", "order"}
+
+// tracer creates the server's spans, or is nil when tracing is not
+// configured, in which case startSpan returns nil spans and every span
+// method is a no-op.
+var tracer trace.Tracer
+
+// traceContext reads and writes W3C traceparent headers.
+var traceContext = propagation.TraceContext{}
+
+type span struct {
+	span trace.Span
+}
+
+// startSpan starts a span as a child of the span in ctx, or of the
+// caller's traceparent, and returns a context carrying it.
+func startSpan(ctx context.Context, name string, kind trace.SpanKind) (context.Context, *span) {
+	if tracer == nil {
+		return ctx, nil
+	}
+	ctx, s := tracer.Start(ctx, name, trace.WithSpanKind(kind))
+	return ctx, &span{span: s}
+}
+
+func (s *span) setAttribute(key, value string) {
+	if s != nil && value != "" {
+		s.span.SetAttributes(attribute.String(key, value))
+	}
+}
+
+// finish ends the span, marking it errored when err is not nil.
+func (s *span) finish(err error) {
+	if s == nil {
+		return
+	}
+	if err != nil {
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+// traceEndpoint returns the OTLP traces URL from the standard OpenTelemetry
+// variables, or "" when tracing is not configured.
+func traceEndpoint() string {
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); endpoint != "" {
+		return endpoint
+	}
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); endpoint != "" {
+		return strings.TrimRight(endpoint, "/") + "/v1/traces"
+	}
+	return ""
+}
+
+// configureTracing starts the span exporter when an OTLP endpoint is set.
+// The returned function flushes queued spans and should run at shutdown.
+func configureTracing() (func(), error) {
+	endpoint := traceEndpoint()
+	if endpoint == "" {
+		return func() {}, nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: must be an http or https URL", endpoint)
+	}
+	protocol := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL")
+	if protocol == "" {
+		protocol = os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+	if protocol != "" && protocol != "http/protobuf" {
+		warnf("OTEL_EXPORTER_OTLP_PROTOCOL %q is not supported; exporting traces with http/protobuf", protocol)
+	}
+	service := os.Getenv("OTEL_SERVICE_NAME")
+	if service == "" {
+		service = defaultTraceServiceName
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(),
+		otlptracehttp.WithEndpointURL(u.String()),
+		otlptracehttp.WithTimeout(traceExportTimeout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create the OTLP exporter: %v", err)
+	}
+	res := resource.NewSchemaless(
+		attribute.String("service.name", service),
+		attribute.String("service.version", serverVersion),
+	)
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(res),
+		sdktrace.WithBatcher(exporter,
+			sdktrace.WithBatchTimeout(traceExportInterval),
+			sdktrace.WithMaxExportBatchSize(traceBatchSize),
+			sdktrace.WithMaxQueueSize(traceQueueLimit),
+		),
+	)
+	// Export failures are dropped with a warning, so a missing collector
+	// never slows tool calls.
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		warnf("Could not export trace spans to %s: %v", u.Redacted(), err)
+	}))
+	tracer = provider.Tracer("github.com/vivsoftorg/mcp-server-enbuild", trace.WithInstrumentationVersion(serverVersion))
+	infof("Exporting traces to %s as service %s", u.Redacted(), service)
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), traceExportTimeout)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			warnf("Could not flush trace spans: %v", err)
+		}
+		tracer = nil
+	}, nil
+}
+
+// traceParentFromHeader makes an incoming W3C traceparent header the parent
+// of the tool spans for that request.
+func traceParentFromHeader(ctx context.Context, r *http.Request) context.Context {
+	return traceContext.Extract(ctx, propagation.HeaderCarrier(r.Header))
+}
+
+// requestContextFromHeaders is the SSE and HTTP context function: it picks
+// up the caller's request ID and trace parent.
+func requestContextFromHeaders(ctx context.Context, r *http.Request) context.Context {
+	return traceParentFromHeader(requestIDFromHeader(ctx, r), r)
+}
+
+// tracingMiddleware wraps each tool call in a span named after the tool,
+// recording its request ID and filter arguments. A call that fails or
+// returns an error response marks the span as errored. It runs inside
+// requestIDMiddleware so the ID is known, and sees JSON responses.
+func tracingMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if tracer == nil {
+			return next(ctx, request)
+		}
+		ctx, s := startSpan(ctx, request.Params.Name, spanKindServer)
+		s.setAttribute("mcp.tool.name", request.Params.Name)
+		if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+			s.setAttribute("enbuild.request_id", id)
+		}
+		args := request.GetArguments()
+		for _, name := range tracedArguments {
+			if value, ok := args[name]; ok {
+				s.setAttribute("enbuild."+name, fmt.Sprint(value))
+			}
+		}
+
+		result, err := next(ctx, request)
+		switch {
+		case err != nil:
+			s.finish(err)
+		case result != nil && result.IsError:
+			var response CatalogResponse
+			if len(result.Content) == 1 {
+				if text, ok := result.Content[0].(mcp.TextContent); ok {
+					json.Unmarshal([]byte(text.Text), &response)
+				}
+			}
+			if response.Message == "" {
+				response.Message = "tool returned an error"
+			}
+			s.setAttribute("enbuild.error_type", response.ErrorType)
+			s.finish(errors.New(response.Message))
+		default:
+			s.finish(nil)
+		}
+		return result, err
+	}
+}
+
+// catalogListAttributes records the filters of an SDK list or get call.
+func catalogListAttributes(s *span, opts *enbuild.CatalogListOptions) {
+	if opts == nil {
+		return
+	}
+	s.setAttribute("enbuild.vcs", opts.VCS)
+	s.setAttribute("enbuild.type", opts.Type)
+	s.setAttribute("enbuild.name", opts.Name)
+}
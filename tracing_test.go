@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestConfigureTracingExportsWithHTTPProtobuf(t *testing.T) {
+	var mu sync.Mutex
+	var contentTypes []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path == "/v1/traces" {
+			contentTypes = append(contentTypes, r.Header.Get("Content-Type"))
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", srv.URL)
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http/protobuf")
+	flush, err := configureTracing()
+	if err != nil {
+		t.Fatalf("tracing with the spec's default protocol failed to start: %v", err)
+	}
+
+	_, s := startSpan(context.Background(), "enbuild.catalogs.get", spanKindClient)
+	s.setAttribute("enbuild.catalog_id", "1")
+	s.finish(nil)
+	flush()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(contentTypes) != 1 || contentTypes[0] != "application/x-protobuf" {
+		t.Errorf("collector received %v, want one application/x-protobuf export", contentTypes)
+	}
+}
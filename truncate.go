@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func maxFieldLength(request mcp.CallToolRequest) (int, error) {
+	if _, ok := request.GetArguments()["max_field_length"]; ok {
+		return getIntArgument(request, "max_field_length")
+	}
+	n, _ := strconv.Atoi(os.Getenv("ENBUILD_MAX_FIELD_LENGTH"))
+	return n, nil
+}
+
+// keepWholeField reports whether the field named key is an identifier or a
+// link, such as id, catalog_id, or web_url, which truncation would make
+// unusable.
+func keepWholeField(key string) bool {
+	lower := strings.ToLower(key)
+	return lower == "id" || lower == "slug" ||
+		strings.HasSuffix(key, "_id") || strings.HasSuffix(key, "Id") || strings.HasSuffix(key, "ID") ||
+		strings.HasSuffix(lower, "url") || strings.HasSuffix(lower, "uri")
+}
+
+// truncateFields shortens every string longer than limit runes found
+// anywhere inside v, returning the new value and how many were shortened.
+// Identifier and link fields (see keepWholeField) and strings that are URLs
+// are left whole.
+func truncateFields(v interface{}, limit int) (interface{}, int) {
+	switch t := v.(type) {
+	case string:
+		n := utf8.RuneCountInString(t)
+		if n <= limit || strings.HasPrefix(t, "https://") || strings.HasPrefix(t, "http://") {
+			return t, 0
+		}
+		runes := []rune(t)
+		return fmt.Sprintf("%s… [truncated %d characters]", string(runes[:limit]), n-limit), 1
+	case map[string]interface{}:
+		total := 0
+		for k, item := range t {
+			if keepWholeField(k) {
+				continue
+			}
+			var count int
+			t[k], count = truncateFields(item, limit)
+			total += count
+		}
+		return t, total
+	case []interface{}:
+		total := 0
+		for i, item := range t {
+			var count int
+			t[i], count = truncateFields(item, limit)
+			total += count
+		}
+		return t, total
+	default:
+		return v, 0
+	}
+}
+
+// truncateFieldsMiddleware applies --max-field-length (or the per-call
+// max_field_length argument) to the data of every tool response.
+func truncateFieldsMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		limit, err := maxFieldLength(request)
+		if err != nil {
+			return formatValidationErrorResponse("Invalid max_field_length value", err)
+		}
+		result, err := next(ctx, request)
+		if err != nil || result == nil || limit <= 0 || len(result.Content) != 1 {
+			return result, err
+		}
+		text, ok := result.Content[0].(mcp.TextContent)
+		if !ok {
+			return result, nil
+		}
+
+		// Decode generically so nested strings can be rewritten; UseNumber
+		// keeps large numeric IDs intact.
+		var response CatalogResponse
+		decoder := json.NewDecoder(strings.NewReader(text.Text))
+		decoder.UseNumber()
+		if err := decoder.Decode(&response); err != nil || response.Data == nil {
+			return result, nil
+		}
+
+		data, count := truncateFields(response.Data, limit)
+		if count == 0 {
+			return result, nil
+		}
+		response.Data = data
+		response.Message += fmt.Sprintf(" (%d string fields truncated to %d characters)", count, limit)
+
+		truncated, err := formatJSONResponse(response)
+		if err != nil {
+			return nil, err
+		}
+		truncated.IsError = result.IsError
+		return truncated, nil
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestTruncateFieldsLongNestedDescription(t *testing.T) {
+	description := strings.Repeat("x", 50)
+	catalog := map[string]interface{}{
+		"name": "vpc",
+		"content": map[string]interface{}{
+			"description": description,
+		},
+	}
+
+	got, count := truncateFields(catalog, 10)
+	if count != 1 {
+		t.Fatalf("truncated %d fields, want 1", count)
+	}
+	content := got.(map[string]interface{})["content"].(map[string]interface{})
+	want := strings.Repeat("x", 10) + "… [truncated 40 characters]"
+	if content["description"] != want {
+		t.Errorf("description = %q, want %q", content["description"], want)
+	}
+	if name := got.(map[string]interface{})["name"]; name != "vpc" {
+		t.Errorf("short field changed to %q", name)
+	}
+}
+
+func TestMaxFieldLengthArgumentOverridesSetting(t *testing.T) {
+	t.Setenv("ENBUILD_MAX_FIELD_LENGTH", "100")
+
+	var request mcp.CallToolRequest
+	if n, err := maxFieldLength(request); err != nil || n != 100 {
+		t.Errorf("without an argument got %d, %v; want the setting, 100", n, err)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"max_field_length": float64(5)}
+	if n, err := maxFieldLength(request); err != nil || n != 5 {
+		t.Errorf("with max_field_length 5 got %d, %v; want 5", n, err)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"max_field_length": float64(0)}
+	if n, err := maxFieldLength(request); err != nil || n != 0 {
+		t.Errorf("with max_field_length 0 got %d, %v; want 0 (disabled)", n, err)
+	}
+}
+
+func TestTruncateFieldsMiddlewareNotesTruncation(t *testing.T) {
+	t.Setenv("ENBUILD_MAX_FIELD_LENGTH", "1000")
+	handler := truncateFieldsMiddleware(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return formatJSONResponse(CatalogResponse{
+			Success: true,
+			Message: "Found 1 catalog",
+			Data: []interface{}{map[string]interface{}{
+				"content": map[string]interface{}{"description": strings.Repeat("d", 30)},
+			}},
+		})
+	})
+
+	var request mcp.CallToolRequest
+	request.Params.Arguments = map[string]interface{}{"max_field_length": "8"}
+	result, err := handler(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	for _, want := range []string{
+		strings.Repeat("d", 8) + "… [truncated 22 characters]",
+		"Found 1 catalog (1 string fields truncated to 8 characters)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("response does not contain %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestTruncateFieldsKeepsURLsAndIDs(t *testing.T) {
+	long := strings.Repeat("a", 40)
+	catalog := map[string]interface{}{
+		"id":           long,
+		"catalog_id":   long,
+		"repositoryId": long,
+		"web_url":      "https://enbuild.example.com/catalogs/" + long,
+		"content": map[string]interface{}{
+			"repository":  "https://github.com/example/" + long,
+			"description": long,
+		},
+	}
+
+	got, count := truncateFields(catalog, 10)
+	if count != 1 {
+		t.Errorf("truncated %d fields, want only the description", count)
+	}
+	m := got.(map[string]interface{})
+	for _, key := range []string{"id", "catalog_id", "repositoryId"} {
+		if m[key] != long {
+			t.Errorf("%s was truncated to %q", key, m[key])
+		}
+	}
+	if m["web_url"] != "https://enbuild.example.com/catalogs/"+long {
+		t.Errorf("web_url was truncated to %q", m["web_url"])
+	}
+	content := m["content"].(map[string]interface{})
+	if content["repository"] != "https://github.com/example/"+long {
+		t.Errorf("repository URL was truncated to %q", content["repository"])
+	}
+}
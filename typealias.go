@@ -0,0 +1,45 @@
+package main
+
+import "strings"
+
+// catalogTypeAliases maps the lower-cased ways users write a catalog type to
+// the value ENBUILD stores; to accept another spelling, add an entry here.
+// It is only a rename table: ENBUILD deployments define their own types, so
+// a type missing from it is passed through rather than rejected.
+var catalogTypeAliases = map[string]string{
+	"terraform":    "terraform",
+	"tf":           "terraform",
+	"ansible":      "ansible",
+	"ansible-role": "ansible",
+	"helm":         "helm",
+	"helm-chart":   "helm",
+	"chart":        "helm",
+	"kubernetes":   "kubernetes",
+	"k8s":          "kubernetes",
+}
+
+// normalizeCatalogType maps one type to its canonical value, ignoring case.
+// Types without an alias are lower-cased and otherwise kept as given.
+func normalizeCatalogType(t string) string {
+	key := strings.ToLower(strings.TrimSpace(t))
+	if canonical, ok := catalogTypeAliases[key]; ok {
+		return canonical
+	}
+	return key
+}
+
+// normalizeCatalogTypeFilter normalizes each type in a comma-separated type
+// filter, so "Terraform,TF" becomes "terraform". An empty filter, or one
+// with no types, matches any type and is returned as "".
+func normalizeCatalogTypeFilter(filter string) string {
+	var types []string
+	seen := map[string]bool{}
+	for _, t := range splitCatalogTypes(filter) {
+		canonical := normalizeCatalogType(t)
+		if !seen[canonical] {
+			seen[canonical] = true
+			types = append(types, canonical)
+		}
+	}
+	return strings.Join(types, ",")
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SupportedVCS lists the VCS providers ENBUILD catalogs can come from. vcs
+// arguments are validated against it, tool descriptions name its entries,
+// and list_vcs returns it, so adding a provider here updates all three.
+var SupportedVCS = []string{"GITHUB", "GITLAB"}
+
+// allVCS is the vcs filter value that searches every supported provider.
+const allVCS = "ALL"
+
+func isSupportedVCS(vcs string) bool {
+	return slices.Contains(SupportedVCS, vcs)
+}
+
+// vcsChoices lists the supported providers, followed by extra, for tool
+// descriptions and error messages: "GITHUB or GITLAB", or with allVCS,
+// "GITHUB, GITLAB, or ALL".
+func vcsChoices(extra ...string) string {
+	choices := append(slices.Clone(SupportedVCS), extra...)
+	switch len(choices) {
+	case 1:
+		return choices[0]
+	case 2:
+		return choices[0] + " or " + choices[1]
+	}
+	return strings.Join(choices[:len(choices)-1], ", ") + ", or " + choices[len(choices)-1]
+}
+
+type vcsProvider struct {
+	Name    string `json:"name"`
+	Default bool   `json:"default,omitempty"`
+}
+
+// listVCS returns the supported providers. It does not contact ENBUILD.
+func listVCS(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	defaultVCS := strings.ToUpper(os.Getenv("ENBUILD_DEFAULT_VCS"))
+	providers := make([]vcsProvider, 0, len(SupportedVCS))
+	for _, vcs := range SupportedVCS {
+		providers = append(providers, vcsProvider{Name: vcs, Default: vcs == defaultVCS})
+	}
+
+	response := CatalogResponse{
+		Success: true,
+		Count:   len(providers),
+		Data:    providers,
+		Message: "Supported VCS values: " + strings.Join(SupportedVCS, ", ") + ". The search tools also accept " + allVCS + " to search every provider",
+	}
+
+	return formatJSONResponse(response)
+}
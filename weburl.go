@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+// defaultCatalogURLTemplate builds a catalog's link in the ENBUILD UI. The
+// UI routes are not part of the API, so deployments whose routes differ can
+// override it with --catalog-url-template.
+const defaultCatalogURLTemplate = "{base_url}/catalogs/{id}"
+
+// catalogWithURL adds the catalog's web link to the catalog's own fields.
+type catalogWithURL struct {
+	*enbuild.Catalog
+	WebURL string `json:"web_url,omitempty"`
+}
+
+func catalogWebURL(baseURL string, c *enbuild.Catalog) string {
+	template := os.Getenv("ENBUILD_CATALOG_URL_TEMPLATE")
+	if template == "" {
+		template = defaultCatalogURLTemplate
+	}
+	return strings.NewReplacer(
+		"{base_url}", strings.TrimRight(baseURL, "/"),
+		"{id}", url.PathEscape(fmt.Sprint(c.ID)),
+		"{slug}", url.PathEscape(c.Slug),
+	).Replace(template)
+}
+
+func withWebURL(baseURL string, c *enbuild.Catalog) catalogWithURL {
+	return catalogWithURL{Catalog: c, WebURL: catalogWebURL(baseURL, c)}
+}
+
+func withWebURLs(baseURL string, catalogs []*enbuild.Catalog) []catalogWithURL {
+	linked := make([]catalogWithURL, len(catalogs))
+	for i, c := range catalogs {
+		linked[i] = withWebURL(baseURL, c)
+	}
+	return linked
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vivsoftorg/enbuild-sdk-go/pkg/enbuild"
+)
+
+// writableFilterWarning explains why list_writable_catalogs returns every
+// catalog: the SDK reports no permission or role data for catalogs or for
+// the authenticated identity, so write access cannot be checked.
+const writableFilterWarning = "write-permission filtering could not be applied because ENBUILD does not expose permission data to this server, so every accessible catalog is returned and some may not be modifiable"
+
+func listWritableCatalogs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	vcs, _ := request.GetArguments()["vcs"].(string)
+	vcs = strings.ToUpper(strings.TrimSpace(vcs))
+	if vcs == "" {
+		vcs = allVCS
+	}
+	if vcs != allVCS && !isSupportedVCS(vcs) {
+		return formatValidationErrorResponse("Invalid VCS value", fmt.Errorf("VCS must be %s", vcsChoices(allVCS)))
+	}
+
+	creds, err := getCredentials(request)
+	if err != nil {
+		return formatCredentialsErrorResponse(err)
+	}
+
+	client, err := initializeClient(ctx, creds)
+	if err != nil {
+		return formatErrorResponse("Failed to initialize ENBUILD client", err)
+	}
+
+	catalogs, warning, err := listFilteredCatalogs(ctx, client, &enbuild.CatalogListOptions{VCS: vcs})
+	if err != nil {
+		return formatCallErrorResponse("Failed to list catalogs", err)
+	}
+
+	response := CatalogResponse{
+		Success: true,
+		Count:   len(catalogs),
+		Data:    withWebURLs(creds.BaseURL, catalogs),
+		Message: fmt.Sprintf("Returned %d catalogs for VCS: %s. Warning: %s", len(catalogs), vcs, writableFilterWarning) + warning,
+	}
+
+	return formatJSONResponse(response)
+}